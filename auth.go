@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/user"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newTokens arma el emisor de tokens según las variables de entorno:
+//
+//	JWT_ALGORITHM         HS256 (por defecto) o RS256
+//	JWT_SECRET            clave de HS256, de al menos 32 bytes
+//	JWT_PRIVATE_KEY_FILE  clave privada RSA en PEM para RS256
+//	JWT_PUBLIC_KEY_FILE   clave pública RSA en PEM; por defecto la de la clave privada
+//	JWT_ACCESS_TTL        duración del token de acceso, por ejemplo 15m
+//	JWT_REFRESH_TTL       duración del token de renovación, por ejemplo 168h
+//
+// Sin JWT_SECRET se genera una clave al azar, así que los tokens dejan de valer al reiniciar.
+func newTokens() (*auth.Tokens, error) {
+	config := auth.Config{Algorithm: os.Getenv("JWT_ALGORITHM")}
+	var err error
+	if config.AccessTTL, err = durationEnv("JWT_ACCESS_TTL"); err != nil {
+		return nil, err
+	}
+	if config.RefreshTTL, err = durationEnv("JWT_REFRESH_TTL"); err != nil {
+		return nil, err
+	}
+
+	switch config.Algorithm {
+	case auth.RS256:
+		if config.PrivateKey, err = privateKey(os.Getenv("JWT_PRIVATE_KEY_FILE")); err != nil {
+			return nil, err
+		}
+		if path := os.Getenv("JWT_PUBLIC_KEY_FILE"); path != "" {
+			if config.PublicKey, err = publicKey(path); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		config.Secret = []byte(os.Getenv("JWT_SECRET"))
+		if len(config.Secret) == 0 {
+			log.Print("JWT_SECRET is not set, using a random key: tokens will not survive a restart")
+			config.Secret = make([]byte, 32)
+			if _, err := rand.Read(config.Secret); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return auth.NewTokens(config)
+}
+
+// bootstrapUsers crea la cuenta de administrador AUTH_USERNAME (admin por defecto) con la
+// contraseña AUTH_PASSWORD si todavía no hay ninguna cuenta.
+func bootstrapUsers(users *user.Service) error {
+	username, password := os.Getenv("AUTH_USERNAME"), os.Getenv("AUTH_PASSWORD")
+	if username == "" {
+		username = "admin"
+	}
+	created, err := users.Bootstrap(username, password)
+	if err != nil {
+		return fmt.Errorf("creating the initial account %q: %w", username, err)
+	}
+	if created {
+		log.Printf("Created the initial admin account %q", username)
+	} else if password == "" {
+		accounts, err := users.List()
+		if err != nil {
+			return err
+		}
+		if len(accounts) == 0 {
+			log.Print("There are no user accounts and AUTH_PASSWORD is not set, logins are disabled")
+		}
+	}
+	return nil
+}
+
+// durationEnv lee una duración de la variable indicada, o 0 si no está definida.
+func durationEnv(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 15m, got %q", name, value)
+	}
+	return d, nil
+}
+
+func privateKey(path string) (*rsa.PrivateKey, error) {
+	if path == "" {
+		return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required with RS256")
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(pem)
+}
+
+func publicKey(path string) (*rsa.PublicKey, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(pem)
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Dialect identifica el motor de base de datos. Las consultas se escriben con placeholders ?
+// y Rebind las adapta al motor.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite3"
+	Postgres Dialect = "postgres"
+)
+
+// DialectOf devuelve el dialecto de una base abierta con Open.
+func DialectOf(database *sql.DB) Dialect {
+	if _, ok := database.Driver().(*pq.Driver); ok {
+		return Postgres
+	}
+	return SQLite
+}
+
+// dialectFor deduce el motor a partir del DSN: las URL postgres:// o postgresql:// y los
+// DSN clave=valor de PostgreSQL usan PostgreSQL; cualquier otro valor es un archivo SQLite.
+func dialectFor(dsn string) Dialect {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") ||
+		strings.HasPrefix(dsn, "host=") || strings.HasPrefix(dsn, "dbname=") {
+		return Postgres
+	}
+	return SQLite
+}
+
+// Rebind reemplaza los placeholders ? por los del dialecto ($1, $2... en PostgreSQL). Los
+// signos ? dentro de literales entre comillas simples se conservan.
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	quoted := false
+	for _, c := range query {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case c == '?' && !quoted:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"database/sql"
+)
+
+// Las bases creadas antes de las migraciones no tienen schema_migrations y pueden tener
+// versiones anteriores de las tablas, creadas con CREATE TABLE IF NOT EXISTS. Al adoptarlas
+// se completan las columnas que faltan y se registra la migración inicial como aplicada.
+
+// isLegacy indica si la base tiene tablas pero nunca se migró.
+func isLegacy(tx *sql.Tx) (bool, error) {
+	var count int
+	err := tx.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'dentists'").Scan(&count)
+	return count > 0, err
+}
+
+// upgradeLegacy agrega las columnas de appointments que se sumaron después de la primera
+// versión del esquema. Las tablas que falten las crea la migración inicial.
+func upgradeLegacy(tx *sql.Tx) error {
+	columns := []struct{ name, definition string }{
+		{"duration", "INTEGER NOT NULL DEFAULT 30"},
+		{"status", "TEXT NOT NULL DEFAULT 'scheduled'"},
+		{"series_id", "INTEGER REFERENCES appointment_series(id)"},
+	}
+	for _, column := range columns {
+		if err := addColumnIfMissing(tx, "appointments", column.name, column.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnIfMissing agrega una columna a una tabla existente si todavía no la tiene.
+func addColumnIfMissing(tx *sql.Tx, table, column, definition string) error {
+	var count int
+	err := tx.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	return err
+}
@@ -0,0 +1,353 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Las migraciones son archivos NNNN_nombre.up.sql y NNNN_nombre.down.sql incluidos en el
+// binario, con un directorio por dialecto. Ambos directorios tienen las mismas versiones y el
+// mismo esquema. Cada migración se aplica en su propia transacción y queda registrada en
+// schema_migrations.
+//
+//go:embed migrations/sqlite/*.sql migrations/postgres/*.sql
+var migrationFiles embed.FS
+
+// migrationDirs indica el directorio de migraciones de cada dialecto.
+var migrationDirs = map[Dialect]string{
+	SQLite:   "migrations/sqlite",
+	Postgres: "migrations/postgres",
+}
+
+// Migration es una versión del esquema con el SQL para aplicarla y revertirla.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus indica si una migración está aplicada y cuándo se aplicó.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt string // RFC 3339, vacío si está pendiente
+}
+
+// ErrPrecheck indica que los datos existentes impiden aplicar una migración. Se corrigen los
+// datos, a mano o con DedupeKeys, y se vuelve a migrar.
+var ErrPrecheck = errors.New("existing data must be fixed before migrating")
+
+// prechecks son controles que se ejecutan antes de aplicar una migración. Si fallan, la
+// migración no se aplica y el error explica qué corregir.
+var prechecks = map[int]func(tx *sql.Tx, dialect Dialect) error{
+	2: checkUniqueKeys,
+}
+
+// Migrations devuelve las migraciones de un dialecto ordenadas por versión.
+func Migrations(dialect Dialect) ([]Migration, error) {
+	files, err := fs.Glob(migrationFiles, migrationDirs[dialect]+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, file := range files {
+		base := path.Base(file)
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql", base)
+		}
+
+		number, name, ok := strings.Cut(strings.TrimSuffix(base, "."+direction+".sql"), "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name", base)
+		}
+
+		content, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, found := byVersion[version]
+		if !found {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d (%s) needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migrations for dialect %s", dialect)
+	}
+	return migrations, nil
+}
+
+// MigrateUp aplica en orden todas las migraciones pendientes y devuelve las aplicadas.
+// Se detiene en la primera que falla; las anteriores quedan aplicadas.
+func MigrateUp(db *sql.DB) ([]Migration, error) {
+	dialect := DialectOf(db)
+	migrations, err := Migrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := apply(db, dialect, migration); err != nil {
+			return done, fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown revierte las últimas steps migraciones aplicadas y devuelve las revertidas.
+func MigrateDown(db *sql.DB, steps int) ([]Migration, error) {
+	dialect := DialectOf(db)
+	migrations, err := Migrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := revert(db, dialect, migration); err != nil {
+			return done, fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status devuelve todas las migraciones con la fecha en que se aplicaron.
+func Status(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations(DialectOf(db))
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status = append(status, MigrationStatus{Version: migration.Version, Name: migration.Name, AppliedAt: applied[migration.Version]})
+	}
+	return status, nil
+}
+
+// appliedVersions devuelve las versiones aplicadas con su fecha, creando la tabla
+// schema_migrations si todavía no existe.
+func appliedVersions(db *sql.DB) (map[int]string, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at TEXT NOT NULL
+    )`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]string{}
+	for rows.Next() {
+		var version int
+		var appliedAt string
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func apply(db *sql.DB, dialect Dialect, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Una base anterior a las migraciones se completa antes de aplicar el esquema inicial.
+	// Solo existen bases así en SQLite.
+	if migration.Version == 1 && dialect == SQLite {
+		legacy, err := isLegacy(tx)
+		if err != nil {
+			return err
+		}
+		if legacy {
+			if err := upgradeLegacy(tx); err != nil {
+				return err
+			}
+		}
+	}
+	if check, ok := prechecks[migration.Version]; ok {
+		if err := check(tx, dialect); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(migration.Up); err != nil {
+		return err
+	}
+	_, err = tx.Exec(dialect.Rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+		migration.Version, migration.Name, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func revert(db *sql.DB, dialect Dialect, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.Down); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(dialect.Rebind("DELETE FROM schema_migrations WHERE version = ?"), migration.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// uniqueKeys son las columnas que la migración 2 vuelve únicas.
+var uniqueKeys = []struct{ table, column string }{{"patients", "dni"}, {"dentists", "license"}}
+
+// checkUniqueKeys controla antes de la migración 2 que no haya DNI ni matrículas repetidas.
+// Si los hay, la migración falla con ErrPrecheck informando cada valor y los registros que
+// lo comparten.
+func checkUniqueKeys(tx *sql.Tx, dialect Dialect) error {
+	ids := "GROUP_CONCAT(id, ', ')"
+	if dialect == Postgres {
+		ids = "STRING_AGG(CAST(id AS TEXT), ', ')"
+	}
+
+	var problems []string
+	for _, key := range uniqueKeys {
+		rows, err := tx.Query("SELECT " + key.column + ", " + ids + " FROM " + key.table +
+			" WHERE " + key.column + " IS NOT NULL AND " + key.column + " != '' GROUP BY " + key.column + " HAVING COUNT(*) > 1")
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var value, ids string
+			if err := rows.Scan(&value, &ids); err != nil {
+				rows.Close()
+				return err
+			}
+			problems = append(problems, fmt.Sprintf("%s %s %q is shared by ids %s", key.table, key.column, value, ids))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s; fix them by hand or run: migrate dedupe", ErrPrecheck, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// DedupeKeys resuelve los DNI y matrículas repetidos que impiden la migración 2: el registro
+// de menor ID conserva el valor y a los demás se les agrega "-<id>", que no es un DNI válido,
+// para que sigan identificándose y se corrijan al editarlos. Devuelve una línea por cambio.
+func DedupeKeys(db *sql.DB) ([]string, error) {
+	dialect := DialectOf(db)
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var changes []string
+	for _, key := range uniqueKeys {
+		present := key.column + " IS NOT NULL AND " + key.column + " != ''"
+		rows, err := tx.Query("SELECT id, " + key.column + " FROM " + key.table + " WHERE " + present +
+			" AND id NOT IN (SELECT MIN(id) FROM " + key.table + " WHERE " + present + " GROUP BY " + key.column + ")" +
+			" AND " + key.column + " IN (SELECT " + key.column + " FROM " + key.table + " GROUP BY " + key.column + " HAVING COUNT(*) > 1)" +
+			" ORDER BY id")
+		if err != nil {
+			return nil, err
+		}
+		type duplicate struct {
+			id    int
+			value string
+		}
+		var duplicates []duplicate
+		for rows.Next() {
+			var d duplicate
+			if err := rows.Scan(&d.id, &d.value); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			duplicates = append(duplicates, d)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, d := range duplicates {
+			value := fmt.Sprintf("%s-%d", d.value, d.id)
+			if _, err := tx.Exec(dialect.Rebind("UPDATE "+key.table+" SET "+key.column+" = ? WHERE id = ?"), value, d.id); err != nil {
+				return nil, err
+			}
+			changes = append(changes, fmt.Sprintf("%s %d: %s %q -> %q", key.table, d.id, key.column, d.value, value))
+		}
+	}
+	return changes, tx.Commit()
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_patients_search;
+ALTER TABLE patients DROP COLUMN IF EXISTS search_vector;
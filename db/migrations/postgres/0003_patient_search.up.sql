@@ -0,0 +1,13 @@
+-- Índice de texto para buscar pacientes por apellido, nombre, DNI o domicilio sin distinguir
+-- mayúsculas ni acentos. Los acentos se quitan con translate() en lugar de la extensión
+-- unaccent para no requerir permisos de superusuario; las letras son las de
+-- textsearch.Accented y textsearch.Plain. La columna generada se mantiene sola al día.
+
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', translate(lower(
+        coalesce(last_name, '') || ' ' || coalesce(first_name, '') || ' ' ||
+        coalesce(dni, '') || ' ' || coalesce(address, '')
+    ), 'àáâãäåçèéêëìíîïñòóôõöùúûüýÿ', 'aaaaaaceeeeiiiinooooouuuuyy'))
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_patients_search ON patients USING GIN (search_vector);
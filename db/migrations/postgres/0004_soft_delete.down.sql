@@ -0,0 +1,33 @@
+-- Los registros eliminados se pierden al revertir: se borran antes de quitar la columna.
+DROP INDEX IF EXISTS idx_appointments_deleted_at;
+DROP INDEX IF EXISTS idx_patients_dni;
+DROP INDEX IF EXISTS idx_dentists_license;
+
+-- También se pierden los turnos activos de pacientes o dentistas eliminados, que quedarían
+-- apuntando a registros borrados.
+DELETE FROM appointment_status_changes WHERE appointment_id IN (SELECT id FROM appointments WHERE deleted_at IS NOT NULL
+    OR patient_id IN (SELECT id FROM patients WHERE deleted_at IS NOT NULL)
+    OR dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL));
+DELETE FROM appointment_reschedules WHERE appointment_id IN (SELECT id FROM appointments WHERE deleted_at IS NOT NULL
+    OR patient_id IN (SELECT id FROM patients WHERE deleted_at IS NOT NULL)
+    OR dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL));
+DELETE FROM appointments WHERE deleted_at IS NOT NULL
+    OR patient_id IN (SELECT id FROM patients WHERE deleted_at IS NOT NULL)
+    OR dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL);
+UPDATE appointments SET series_id = NULL WHERE series_id IN (SELECT id FROM appointment_series
+    WHERE patient_id IN (SELECT id FROM patients WHERE deleted_at IS NOT NULL)
+    OR dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL));
+DELETE FROM appointment_series WHERE patient_id IN (SELECT id FROM patients WHERE deleted_at IS NOT NULL)
+    OR dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL);
+DELETE FROM dentist_schedule_breaks WHERE schedule_id IN (SELECT id FROM dentist_schedules
+    WHERE dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL));
+DELETE FROM dentist_schedules WHERE dentist_id IN (SELECT id FROM dentists WHERE deleted_at IS NOT NULL);
+DELETE FROM patients WHERE deleted_at IS NOT NULL;
+DELETE FROM dentists WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE appointments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE patients DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE dentists DROP COLUMN IF EXISTS deleted_at;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_patients_dni ON patients (dni) WHERE dni IS NOT NULL AND dni != '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dentists_license ON dentists (license) WHERE license IS NOT NULL AND license != '';
@@ -0,0 +1,15 @@
+-- Baja lógica de dentistas, pacientes y turnos: deleted_at guarda cuándo se eliminó el
+-- registro (RFC 3339, UTC) y queda en NULL mientras está activo. Los registros eliminados se
+-- borran definitivamente al vencer la retención (ver paquete retention).
+
+ALTER TABLE dentists ADD COLUMN IF NOT EXISTS deleted_at TEXT;
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS deleted_at TEXT;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS deleted_at TEXT;
+
+-- El DNI y la matrícula solo deben ser únicos entre los registros activos
+DROP INDEX IF EXISTS idx_patients_dni;
+DROP INDEX IF EXISTS idx_dentists_license;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_patients_dni ON patients (dni) WHERE dni IS NOT NULL AND dni != '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dentists_license ON dentists (license) WHERE license IS NOT NULL AND license != '' AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appointments_deleted_at ON appointments (deleted_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_username;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_log_timestamp;
+DROP INDEX IF EXISTS idx_audit_log_actor;
+DROP INDEX IF EXISTS idx_audit_log_resource;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,17 @@
+-- Registro de auditoría de las altas, modificaciones y bajas de dentistas, pacientes y turnos.
+-- changes guarda en JSON los campos que cambiaron con su valor anterior y posterior. Los
+-- registros no se modifican ni se borran, tampoco al purgar los eliminados.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    timestamp TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    resource_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    changes TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log (resource, resource_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_log_timestamp ON audit_log (timestamp);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS appointment_reschedules;
+DROP TABLE IF EXISTS appointment_status_changes;
+DROP TABLE IF EXISTS dentist_schedule_breaks;
+DROP TABLE IF EXISTS dentist_schedules;
+DROP TABLE IF EXISTS appointments;
+DROP TABLE IF EXISTS appointment_series;
+DROP TABLE IF EXISTS patients;
+DROP TABLE IF EXISTS dentists;
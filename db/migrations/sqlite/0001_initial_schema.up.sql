@@ -0,0 +1,93 @@
+-- Esquema inicial. IF NOT EXISTS permite adoptar bases creadas antes de las migraciones.
+
+CREATE TABLE IF NOT EXISTS dentists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    last_name TEXT,
+    first_name TEXT,
+    license TEXT
+);
+
+CREATE TABLE IF NOT EXISTS patients (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    last_name TEXT,
+    first_name TEXT,
+    address TEXT,
+    dni TEXT,
+    registration_date TEXT
+);
+
+CREATE TABLE IF NOT EXISTS appointment_series (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    patient_id INTEGER NOT NULL,
+    dentist_id INTEGER NOT NULL,
+    start_date TEXT NOT NULL,
+    time TEXT NOT NULL,
+    duration INTEGER NOT NULL,
+    description TEXT,
+    frequency TEXT NOT NULL,
+    repeat_interval INTEGER NOT NULL DEFAULT 1,
+    occurrence_count INTEGER NOT NULL DEFAULT 0,
+    until_date TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY(patient_id) REFERENCES patients(id),
+    FOREIGN KEY(dentist_id) REFERENCES dentists(id)
+);
+
+CREATE TABLE IF NOT EXISTS appointments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date TEXT,
+    time TEXT,
+    duration INTEGER NOT NULL DEFAULT 30,
+    description TEXT,
+    status TEXT NOT NULL DEFAULT 'scheduled',
+    patient_id INTEGER,
+    dentist_id INTEGER,
+    series_id INTEGER,
+    FOREIGN KEY(patient_id) REFERENCES patients(id),
+    FOREIGN KEY(dentist_id) REFERENCES dentists(id),
+    FOREIGN KEY(series_id) REFERENCES appointment_series(id)
+);
+
+CREATE TABLE IF NOT EXISTS dentist_schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dentist_id INTEGER NOT NULL,
+    day_of_week INTEGER NOT NULL,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    FOREIGN KEY(dentist_id) REFERENCES dentists(id)
+);
+
+CREATE TABLE IF NOT EXISTS dentist_schedule_breaks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    schedule_id INTEGER NOT NULL,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    FOREIGN KEY(schedule_id) REFERENCES dentist_schedules(id)
+);
+
+CREATE TABLE IF NOT EXISTS appointment_status_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    appointment_id INTEGER NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    changed_at TEXT NOT NULL,
+    FOREIGN KEY(appointment_id) REFERENCES appointments(id)
+);
+
+CREATE TABLE IF NOT EXISTS appointment_reschedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    appointment_id INTEGER NOT NULL,
+    previous_date TEXT NOT NULL,
+    previous_time TEXT NOT NULL,
+    previous_duration INTEGER NOT NULL,
+    previous_dentist_id INTEGER NOT NULL,
+    new_date TEXT NOT NULL,
+    new_time TEXT NOT NULL,
+    new_duration INTEGER NOT NULL,
+    new_dentist_id INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by TEXT NOT NULL,
+    changed_at TEXT NOT NULL,
+    FOREIGN KEY(appointment_id) REFERENCES appointments(id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dentists_license;
+DROP INDEX IF EXISTS idx_patients_dni;
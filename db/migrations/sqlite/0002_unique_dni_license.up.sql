@@ -0,0 +1,5 @@
+-- Claves por las que se buscan pacientes y dentistas. Los valores vacíos no se indexan.
+-- Antes de aplicarla se controla que no haya duplicados (ver checkUniqueKeys).
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_patients_dni ON patients (dni) WHERE dni IS NOT NULL AND dni != '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dentists_license ON dentists (license) WHERE license IS NOT NULL AND license != '';
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS patients_search_after_insert;
+DROP TRIGGER IF EXISTS patients_search_after_update;
+DROP TRIGGER IF EXISTS patients_search_before_delete;
+DROP TRIGGER IF EXISTS patients_search_before_update;
+DROP TABLE IF EXISTS patients_search;
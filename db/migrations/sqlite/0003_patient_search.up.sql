@@ -0,0 +1,28 @@
+-- Índice de texto para buscar pacientes por apellido, nombre, DNI o domicilio sin distinguir
+-- mayúsculas ni acentos. FTS4 se usa porque FTS5 no está incluido en go-sqlite3 sin build
+-- tags. Los triggers mantienen el índice al día con la tabla patients.
+
+CREATE VIRTUAL TABLE IF NOT EXISTS patients_search USING fts4(
+    content="patients", last_name, first_name, dni, address,
+    tokenize=unicode61 "remove_diacritics=1"
+);
+
+INSERT INTO patients_search (patients_search) VALUES ('rebuild');
+
+CREATE TRIGGER IF NOT EXISTS patients_search_before_update BEFORE UPDATE ON patients BEGIN
+    DELETE FROM patients_search WHERE docid = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS patients_search_before_delete BEFORE DELETE ON patients BEGIN
+    DELETE FROM patients_search WHERE docid = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS patients_search_after_update AFTER UPDATE ON patients BEGIN
+    INSERT INTO patients_search (docid, last_name, first_name, dni, address)
+    VALUES (new.id, new.last_name, new.first_name, new.dni, new.address);
+END;
+
+CREATE TRIGGER IF NOT EXISTS patients_search_after_insert AFTER INSERT ON patients BEGIN
+    INSERT INTO patients_search (docid, last_name, first_name, dni, address)
+    VALUES (new.id, new.last_name, new.first_name, new.dni, new.address);
+END;
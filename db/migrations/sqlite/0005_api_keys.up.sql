@@ -0,0 +1,16 @@
+-- Claves de API de las integraciones. Solo se guarda el SHA-256 de la clave; prefix son sus
+-- primeros caracteres, para reconocerla en los listados. scopes son los permisos separados
+-- por espacios. Las claves revocadas se conservan con revoked_at.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    scopes TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    last_used_at TEXT,
+    revoked_at TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,18 @@
+-- Cuentas de usuario. password_hash es un hash bcrypt; username se guarda en minúsculas.
+-- failed_logins cuenta los intentos fallidos seguidos y locked_until bloquea la cuenta hasta
+-- ese momento (RFC 3339). Los usuarios con el rol dentist se vinculan a su dentista.
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    role TEXT NOT NULL,
+    dentist_id INTEGER,
+    failed_logins INTEGER NOT NULL DEFAULT 0,
+    locked_until TEXT,
+    password_changed_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY(dentist_id) REFERENCES dentists(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
@@ -15,28 +15,52 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/dentistas": {
+        "/api-keys/": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Incluye las revocadas. La clave en sí no se puede consultar; prefix permite reconocerla.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentista"
+                    "Claves de API"
                 ],
-                "summary": "Listar todos los dentistas",
+                "summary": "Listar las claves de API",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Dentist"
+                                "$ref": "#/definitions/models.APIKey"
                             }
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     }
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "La clave se muestra solo en esta respuesta: debe guardarse en ese momento.\nSe envía en el encabezado X-API-Key y tiene solo los permisos indicados en scopes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -44,17 +68,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dentista"
+                    "Claves de API"
                 ],
-                "summary": "Agregar un nuevo dentista",
+                "summary": "Crear una clave de API",
                 "parameters": [
                     {
-                        "description": "Dentista",
-                        "name": "dentista",
+                        "description": "Nombre y permisos",
+                        "name": "clave",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Dentist"
+                            "$ref": "#/definitions/models.APIKeyRequest"
                         }
                     }
                 ],
@@ -62,11 +86,29 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Dentist"
+                            "$ref": "#/definitions/models.NewAPIKey"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
@@ -74,29 +116,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/dentistas/{id}": {
-            "get": {
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "La clave deja de autenticar de inmediato y sigue apareciendo en el listado con revoked_at.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentista"
+                    "Claves de API"
                 ],
-                "summary": "Obtener un dentista por ID",
+                "summary": "Revocar una clave de API",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID del dentista",
+                        "description": "ID de la clave",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.Dentist"
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
                         }
                     },
                     "404": {
@@ -106,8 +163,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/appointment-series/": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Genera un turno por cada ocurrencia de la serie. Las ocurrencias que se superponen\ncon otros turnos o caen fuera del horario del dentista se omiten y se informan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -115,62 +183,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dentista"
+                    "Serie"
                 ],
-                "summary": "Actualizar un dentista",
+                "summary": "Crear una serie de turnos recurrentes",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID del dentista",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Dentista",
-                        "name": "dentista",
+                        "description": "Serie",
+                        "name": "serie",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Dentist"
+                            "$ref": "#/definitions/models.AppointmentSeries"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Dentist"
+                            "$ref": "#/definitions/models.SeriesResult"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/appointment-series/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Dentista"
+                    "Serie"
                 ],
-                "summary": "Eliminar un dentista",
+                "summary": "Obtener una serie de turnos con sus ocurrencias",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID del dentista",
+                        "description": "ID de la serie",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AppointmentSeries"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -179,8 +271,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/appointment-series/{id}/appointments/{appointmentId}": {
             "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cambia hora, duración, dentista o descripción. Los turnos que ya no están\npendientes o que quedarían en conflicto se omiten y se informan. Los turnos que\ncambian de horario o dentista quedan en su historial de reprogramaciones con el motivo indicado.",
                 "consumes": [
                     "application/json"
                 ],
@@ -188,23 +291,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Dentista"
+                    "Serie"
                 ],
-                "summary": "Actualizar algunos campos de un dentista",
+                "summary": "Modificar un turno de una serie, ese y los siguientes, o toda la serie",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID del dentista",
+                        "description": "ID de la serie",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del turno de la serie",
+                        "name": "appointmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "this (por defecto), following o all",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cambios",
+                        "name": "cambios",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeriesUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Dentist"
+                            "$ref": "#/definitions/models.SeriesResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
                         }
                     },
                     "404": {
@@ -212,32 +349,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
-                    }
-                }
-            }
-        },
-        "/pacientes": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Paciente"
-                ],
-                "summary": "Listar todos los pacientes",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Patient"
-                            }
+                            "$ref": "#/definitions/models.Error"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/appointments/by-reference": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -245,17 +376,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Paciente"
+                    "Turno"
                 ],
-                "summary": "Agregar un nuevo paciente",
+                "summary": "Agregar un nuevo turno usando DNI del paciente y matrícula del dentista",
                 "parameters": [
                     {
-                        "description": "Paciente",
-                        "name": "paciente",
+                        "description": "Turno",
+                        "name": "turno",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Patient"
+                            "$ref": "#/definitions/models.AppointmentByReference"
                         }
                     }
                 ],
@@ -263,35 +394,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Patient"
+                            "$ref": "#/definitions/models.Appointment"
                         }
-                    }
-                }
-            }
-        },
-        "/pacientes/{id}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Paciente"
-                ],
-                "summary": "Obtener un paciente por ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID del paciente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.Patient"
+                            "$ref": "#/definitions/models.Error"
                         }
                     },
                     "404": {
@@ -299,10 +408,32 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/appointments/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -310,24 +441,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Paciente"
+                    "Turno"
                 ],
-                "summary": "Actualizar un paciente",
+                "summary": "Cancelar un turno indicando el motivo",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID del paciente",
+                        "description": "ID del turno",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Paciente",
-                        "name": "paciente",
+                        "description": "Motivo",
+                        "name": "cambio",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Patient"
+                            "$ref": "#/definitions/models.StatusChangeRequest"
                         }
                     }
                 ],
@@ -335,7 +466,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Patient"
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
                         }
                     },
                     "404": {
@@ -343,39 +480,32 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Paciente"
-                ],
-                "summary": "Eliminar un paciente",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID del paciente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/appointments/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -383,23 +513,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Paciente"
+                    "Turno"
                 ],
-                "summary": "Actualizar algunos campos de un paciente",
+                "summary": "Marcar un turno como completado",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID del paciente",
+                        "description": "ID del turno",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Motivo",
+                        "name": "cambio",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.StatusChangeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Patient"
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
                         }
                     },
                     "404": {
@@ -407,32 +551,26 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
-                    }
-                }
-            }
-        },
-        "/turnos": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Turno"
-                ],
-                "summary": "Listar todos los turnos",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Appointment"
-                            }
+                            "$ref": "#/definitions/models.Error"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/appointments/{id}/confirm": {
             "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -442,37 +580,72 @@ const docTemplate = `{
                 "tags": [
                     "Turno"
                 ],
-                "summary": "Agregar un nuevo turno",
+                "summary": "Confirmar un turno",
                 "parameters": [
                     {
-                        "description": "Turno",
-                        "name": "turno",
+                        "type": "integer",
+                        "description": "ID del turno",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Motivo",
+                        "name": "cambio",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Appointment"
+                            "$ref": "#/definitions/models.StatusChangeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/models.Appointment"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     }
                 }
             }
         },
-        "/turnos/{id}": {
-            "get": {
+        "/appointments/{id}/no-show": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Turno"
                 ],
-                "summary": "Obtener un turno por ID",
+                "summary": "Marcar que el paciente no se presentó al turno",
                 "parameters": [
                     {
                         "type": "integer",
@@ -480,6 +653,14 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Motivo",
+                        "name": "cambio",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.StatusChangeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -489,15 +670,37 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Appointment"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/appointments/{id}/reschedule": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -507,7 +710,7 @@ const docTemplate = `{
                 "tags": [
                     "Turno"
                 ],
-                "summary": "Actualizar un turno",
+                "summary": "Mover un turno a otra fecha, hora o dentista conservando el historial",
                 "parameters": [
                     {
                         "type": "integer",
@@ -517,12 +720,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Turno",
-                        "name": "turno",
+                        "description": "Nuevo horario",
+                        "name": "reprogramacion",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Appointment"
+                            "$ref": "#/definitions/models.RescheduleRequest"
                         }
                     }
                 ],
@@ -533,22 +736,56 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Appointment"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/appointments/{id}/reschedules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Turno"
                 ],
-                "summary": "Eliminar un turno",
+                "summary": "Obtener los horarios anteriores de un turno reprogramado",
                 "parameters": [
                     {
                         "type": "integer",
@@ -559,8 +796,20 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reschedule"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -569,10 +818,17 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/appointments/{id}/status-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
@@ -580,7 +836,7 @@ const docTemplate = `{
                 "tags": [
                     "Turno"
                 ],
-                "summary": "Actualizar algunos campos de un turno",
+                "summary": "Obtener el historial de cambios de estado de un turno",
                 "parameters": [
                     {
                         "type": "integer",
@@ -594,7 +850,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Appointment"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StatusChange"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
                         }
                     },
                     "404": {
@@ -605,68 +870,3036 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Appointment": {
-            "type": "object",
-            "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "dentist_id": {
-                    "type": "integer"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "patient_id": {
-                    "type": "integer"
-                },
-                "time": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Dentist": {
-            "type": "object",
-            "properties": {
-                "first_name": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "last_name": {
-                    "type": "string"
-                },
-                "license": {
-                    "type": "string"
-                }
-            }
         },
-        "models.Error": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "description": "Código del error (por ejemplo, 400, 404)",
-                    "type": "integer"
-                },
-                "message": {
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de los cambios hechos sobre dentistas, pacientes, turnos y series,\ncon quién los hizo y los campos que cambiaron. El total sin paginar va en X-Total-Count\ny los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auditoría"
+                ],
+                "summary": "Consultar el registro de auditoría",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recurso: dentist, patient, appointment o series",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del recurso",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Usuario o clave de API que hizo el cambio",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde esta fecha (YYYY-MM-DD) o momento (RFC 3339), inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta esta fecha (YYYY-MM-DD) o momento (RFC 3339), inclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de orden separados por comas, con - para orden descendente: id, timestamp, actor, resource, resource_id, action",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de registros sin paginar"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Devuelve un token de acceso, a enviar como \"Authorization: Bearer \u003ctoken\u003e\",\ny un token de renovación para pedir uno nuevo cuando venza.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Autenticación"
+                ],
+                "summary": "Iniciar sesión",
+                "parameters": [
+                    {
+                        "description": "Usuario y contraseña",
+                        "name": "credenciales",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Autenticación"
+                ],
+                "summary": "Obtener el usuario autenticado",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requiere la contraseña actual; si es incorrecta cuenta como intento fallido.\nLos tokens de renovación emitidos antes dejan de servir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Autenticación"
+                ],
+                "summary": "Cambiar la contraseña propia",
+                "parameters": [
+                    {
+                        "description": "Contraseña actual y nueva",
+                        "name": "contraseña",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordChange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Cambia un token de renovación vigente por un nuevo par de tokens con los datos\nactuales de la cuenta. Los tokens emitidos antes de un cambio de contraseña no se renuevan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Autenticación"
+                ],
+                "summary": "Renovar los tokens",
+                "parameters": [
+                    {
+                        "description": "Token de renovación",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenPair"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/availability": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Disponibilidad"
+                ],
+                "summary": "Buscar horarios libres de todos los dentistas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fecha desde (YYYY-MM-DD), por defecto hoy",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha hasta (YYYY-MM-DD), por defecto una semana después de from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Duración del turno en minutos",
+                        "name": "duration",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AvailableSlot"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/dentistas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de dentistas. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Listar todos los dentistas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Apellido o comienzo del apellido, sin distinguir mayúsculas",
+                        "name": "last_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir los dentistas eliminados (requiere el permiso dentists:admin)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de orden separados por comas, con - para orden descendente: id, last_name, first_name, license",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Dentist"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de registros sin paginar"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Agregar un nuevo dentista",
+                "parameters": [
+                    {
+                        "description": "Dentista",
+                        "name": "dentista",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/dentistas/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Obtener un dentista por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Actualizar un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dentista",
+                        "name": "dentista",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Es una baja lógica: el dentista deja de aparecer en los listados y puede restaurarse.\nLa política indica qué hacer con sus turnos reservados o confirmados: restrict (por\ndefecto) rechaza la eliminación si tiene alguno, cascade elimina todos sus turnos y\nreassign los pasa al dentista reassign_to, que debe tener el horario libre y de atención.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Eliminar un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "restrict, cascade o reassign",
+                        "name": "policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista que recibe los turnos (policy=reassign)",
+                        "name": "reassign_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Actualizar algunos campos de un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/dentistas/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "También restaura los turnos que se eliminaron con él, salvo los de pacientes eliminados. Falla si otro dentista activo tiene la misma matrícula.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Restaurar un dentista eliminado",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dentist"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/appointments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de los turnos del dentista. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Dentista"
+                ],
+                "summary": "Listar los turnos de un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "patient_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha desde, inclusive (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha hasta, inclusive (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Estado: scheduled, confirmed, completed, cancelled o no_show",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Datos a incluir en cada turno: patient, dentist",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir los turnos eliminados (requiere el permiso appointments:admin)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de orden separados por comas, con - para orden descendente: id, date, time, duration, status, patient_id, dentist_id",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExpandedAppointment"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de registros sin paginar"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/availability": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Disponibilidad"
+                ],
+                "summary": "Buscar horarios libres de un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha desde (YYYY-MM-DD), por defecto hoy",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha hasta (YYYY-MM-DD), por defecto una semana después de from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Duración del turno en minutos",
+                        "name": "duration",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AvailableSlot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/dentists/{id}/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agenda"
+                ],
+                "summary": "Obtener los horarios de atención de un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleBlock"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agenda"
+                ],
+                "summary": "Reemplazar los horarios de atención de un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Franjas de atención",
+                        "name": "agenda",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleBlock"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduleBlock"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Agenda"
+                ],
+                "summary": "Eliminar los horarios de atención de un dentista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/pacientes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de pacientes. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Listar todos los pacientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Apellido o comienzo del apellido, sin distinguir mayúsculas",
+                        "name": "last_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir los pacientes eliminados (requiere el permiso patients:admin)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de orden separados por comas, con - para orden descendente: id, last_name, first_name, dni, registration_date",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Patient"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de registros sin paginar"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Los usuarios limitados a un dentista no pueden dar de alta pacientes: el paciente\nnuevo no tiene turnos con ese dentista y no podrían verlo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Agregar un nuevo paciente",
+                "parameters": [
+                    {
+                        "description": "Paciente",
+                        "name": "paciente",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/pacientes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Obtener un paciente por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Actualizar un paciente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Paciente",
+                        "name": "paciente",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Es una baja lógica: el paciente deja de aparecer en los listados y puede restaurarse.\nLa política indica qué hacer con sus turnos reservados o confirmados: restrict (por\ndefecto) rechaza la eliminación si tiene alguno y cascade elimina todos sus turnos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Eliminar un paciente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "restrict o cascade",
+                        "name": "policy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Actualizar algunos campos de un paciente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DuplicateError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/pacientes/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "También restaura los turnos que se eliminaron con él, salvo los de dentistas eliminados. Falla si otro paciente activo tiene el mismo DNI.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Restaurar un paciente eliminado",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Patient"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Busca por apellido, nombre, DNI o domicilio sin distinguir mayúsculas ni acentos. Cada palabra debe ser el comienzo de alguna palabra del paciente. Los resultados se ordenan por relevancia; el total va en X-Total-Count y los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Buscar pacientes por texto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texto a buscar, por ejemplo: perez juan",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Patient"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de resultados sin paginar"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/patients/{id}/appointments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de los turnos del paciente. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paciente"
+                ],
+                "summary": "Listar los turnos de un paciente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "dentist_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha desde, inclusive (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha hasta, inclusive (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Estado: scheduled, confirmed, completed, cancelled o no_show",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Datos a incluir en cada turno: patient, dentist",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir los turnos eliminados (requiere el permiso appointments:admin)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de orden separados por comas, con - para orden descendente: id, date, time, duration, status, patient_id, dentist_id",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExpandedAppointment"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de registros sin paginar"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/turnos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de turnos. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Listar todos los turnos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del dentista",
+                        "name": "dentist_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del paciente",
+                        "name": "patient_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha desde, inclusive (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha hasta, inclusive (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Estado: scheduled, confirmed, completed, cancelled o no_show",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Datos a incluir en cada turno: patient, dentist",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir los turnos eliminados (requiere el permiso appointments:admin)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros por página (1 a 500, por defecto 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cantidad de registros a saltear",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de orden separados por comas, con - para orden descendente: id, date, time, duration, status, patient_id, dentist_id",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExpandedAppointment"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de registros sin paginar"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Agregar un nuevo turno",
+                "parameters": [
+                    {
+                        "description": "Turno",
+                        "name": "turno",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/turnos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Obtener un turno por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del turno",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Datos a incluir en el turno: patient, dentist",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpandedAppointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "La fecha, la hora, la duración y el dentista deben ser los actuales: para moverlo\nse usa POST /appointments/{id}/reschedule, que guarda el horario anterior.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Actualizar un turno",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del turno",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Turno",
+                        "name": "turno",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Es una baja lógica: el turno deja de aparecer en los listados, conserva sus historiales y puede restaurarse.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Eliminar un turno",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del turno",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "No puede cambiar la fecha, la hora, la duración ni el dentista: para moverlo se\nusa POST /appointments/{id}/reschedule, que guarda el horario anterior.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Actualizar algunos campos de un turno",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del turno",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConflictError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/turnos/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "El paciente y el dentista deben estar activos y el turno no debe superponerse con otro.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Turno"
+                ],
+                "summary": "Restaurar un turno eliminado",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del turno",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuarios"
+                ],
+                "summary": "Listar las cuentas de usuario",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserAccount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "La contraseña debe tener al menos 10 caracteres y como máximo 72 bytes, letras y\nnúmeros, y no puede contener el nombre de usuario. El rol dentist requiere dentist_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuarios"
+                ],
+                "summary": "Crear una cuenta de usuario",
+                "parameters": [
+                    {
+                        "description": "Datos de la cuenta",
+                        "name": "usuario",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "También desbloquea la cuenta. Los tokens de renovación emitidos antes dejan de servir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Usuarios"
+                ],
+                "summary": "Asignar una nueva contraseña a una cuenta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la cuenta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nueva contraseña",
+                        "name": "contraseña",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordReset"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAccount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "description": "RFC 3339, vacío si nunca se usó",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Integración que usa la clave",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Primeros caracteres de la clave, para reconocerla",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "description": "RFC 3339, solo en las claves revocadas",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Permisos, por ejemplo appointments:read",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.APIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Integración que usa la clave",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
+                    "description": "Permisos, por ejemplo appointments:read",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Appointment": {
+            "type": "object",
+            "required": [
+                "date",
+                "dentist_id",
+                "patient_id",
+                "time"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "RFC 3339, solo en los registros eliminados",
+                    "type": "string"
+                },
+                "dentist_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "duration": {
+                    "description": "Duración en minutos",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "end_time": {
+                    "description": "Calculado a partir de time y duration",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "patient_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "series_id": {
+                    "description": "Serie recurrente a la que pertenece, si corresponde",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "scheduled, confirmed, completed, cancelled o no_show",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AppointmentByReference": {
+            "type": "object",
+            "required": [
+                "date",
+                "dentist_license",
+                "patient_dni",
+                "time"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "dentist_license": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "duration": {
+                    "description": "Duración en minutos",
+                    "type": "integer"
+                },
+                "patient_dni": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AppointmentSeries": {
+            "type": "object",
+            "required": [
+                "dentist_id",
+                "frequency",
+                "patient_id",
+                "start_date",
+                "time"
+            ],
+            "properties": {
+                "appointments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Appointment"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "maximum": 104,
+                    "minimum": 0
+                },
+                "dentist_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "duration": {
+                    "description": "Duración en minutos",
+                    "type": "integer"
+                },
+                "frequency": {
+                    "description": "weekly o monthly",
+                    "type": "string",
+                    "enum": [
+                        "weekly|monthly"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "description": "Cada cuántas semanas o meses, por defecto 1",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "patient_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "start_date": {
+                    "description": "YYYY-MM-DD, primera ocurrencia",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "until": {
+                    "description": "YYYY-MM-DD, inclusive",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "create, update, patch, delete, restore, status o reschedule",
+                    "type": "string",
+                    "example": "patch"
+                },
+                "actor": {
+                    "description": "Usuario o clave de API que hizo el cambio",
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Campos que cambiaron, por nombre JSON",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "appointment"
+                },
+                "resource_id": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AvailableSlot": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "dentist_id": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConflictError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Código del error (por ejemplo, 400, 404)",
+                    "type": "integer"
+                },
+                "conflicting_appointment_id": {
+                    "description": "ID del turno con el que choca",
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Errores por campo, si corresponde",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "error": {
+                    "description": "Tipo de error legible por máquina (por ejemplo, not_found)",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Mensaje descriptivo del error",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Dentist": {
+            "type": "object",
+            "required": [
+                "first_name",
+                "last_name",
+                "license"
+            ],
+            "properties": {
+                "deleted_at": {
+                    "description": "RFC 3339, solo en los registros eliminados",
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "license": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DentistSummary": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "license": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DuplicateError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Código del error (por ejemplo, 400, 404)",
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Errores por campo, si corresponde",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "error": {
+                    "description": "Tipo de error legible por máquina (por ejemplo, not_found)",
+                    "type": "string"
+                },
+                "existing_id": {
+                    "description": "ID del registro que ya usa el valor",
+                    "type": "integer"
+                },
+                "field": {
+                    "description": "Campo con el valor repetido",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Mensaje descriptivo del error",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Error": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Código del error (por ejemplo, 400, 404)",
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Errores por campo, si corresponde",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "error": {
+                    "description": "Tipo de error legible por máquina (por ejemplo, not_found)",
+                    "type": "string"
+                },
+                "message": {
                     "description": "Mensaje descriptivo del error",
                     "type": "string"
                 }
             }
         },
+        "models.ExpandedAppointment": {
+            "type": "object",
+            "required": [
+                "date",
+                "dentist_id",
+                "patient_id",
+                "time"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "RFC 3339, solo en los registros eliminados",
+                    "type": "string"
+                },
+                "dentist": {
+                    "$ref": "#/definitions/models.DentistSummary"
+                },
+                "dentist_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "duration": {
+                    "description": "Duración en minutos",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "end_time": {
+                    "description": "Calculado a partir de time y duration",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "patient": {
+                    "$ref": "#/definitions/models.PatientSummary"
+                },
+                "patient_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "series_id": {
+                    "description": "Serie recurrente a la que pertenece, si corresponde",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "scheduled, confirmed, completed, cancelled o no_show",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Nombre del campo en el JSON",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Qué está mal en el campo",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "Contraseña",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Nombre de usuario",
+                    "type": "string"
+                }
+            }
+        },
+        "models.NewAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Clave a enviar en el encabezado X-API-Key",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "description": "RFC 3339, vacío si nunca se usó",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Integración que usa la clave",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Primeros caracteres de la clave, para reconocerla",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "description": "RFC 3339, solo en las claves revocadas",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Permisos, por ejemplo appointments:read",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PasswordChange": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PasswordReset": {
+            "type": "object",
+            "required": [
+                "new_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Patient": {
             "type": "object",
+            "required": [
+                "dni",
+                "first_name",
+                "last_name"
+            ],
             "properties": {
                 "address": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "deleted_at": {
+                    "description": "RFC 3339, solo en los registros eliminados",
+                    "type": "string"
+                },
+                "dni": {
                     "type": "string"
                 },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "registration_date": {
+                    "description": "YYYY-MM-DD, por defecto la fecha del alta",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PatientSummary": {
+            "type": "object",
+            "properties": {
                 "dni": {
                     "type": "string"
                 },
@@ -678,12 +3911,354 @@ const docTemplate = `{
                 },
                 "last_name": {
                     "type": "string"
+                }
+            }
+        },
+        "models.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "Token de renovación recibido al iniciar sesión",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Reschedule": {
+            "type": "object",
+            "properties": {
+                "appointment_id": {
+                    "type": "integer"
+                },
+                "changed_at": {
+                    "description": "RFC 3339",
+                    "type": "string"
                 },
-                "registration_date": {
+                "changed_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_date": {
+                    "type": "string"
+                },
+                "new_dentist_id": {
+                    "type": "integer"
+                },
+                "new_duration": {
+                    "type": "integer"
+                },
+                "new_time": {
+                    "type": "string"
+                },
+                "previous_date": {
+                    "type": "string"
+                },
+                "previous_dentist_id": {
+                    "type": "integer"
+                },
+                "previous_duration": {
+                    "type": "integer"
+                },
+                "previous_time": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RescheduleRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "time"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "dentist_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduleBlock": {
+            "type": "object",
+            "properties": {
+                "breaks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduleBreak"
+                    }
+                },
+                "day_of_week": {
+                    "description": "0 = domingo ... 6 = sábado",
+                    "type": "integer"
+                },
+                "dentist_id": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduleBreak": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SeriesResult": {
+            "type": "object",
+            "properties": {
+                "appointments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Appointment"
+                    }
+                },
+                "series": {
+                    "$ref": "#/definitions/models.AppointmentSeries"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SkippedOccurrence"
+                    }
+                }
+            }
+        },
+        "models.SeriesUpdate": {
+            "type": "object",
+            "properties": {
+                "dentist_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Motivo que queda en el historial de reprogramaciones",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SkippedOccurrence": {
+            "type": "object",
+            "properties": {
+                "appointment_id": {
+                    "type": "integer"
+                },
+                "conflicting_appointment_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StatusChange": {
+            "type": "object",
+            "properties": {
+                "appointment_id": {
+                    "type": "integer"
+                },
+                "changed_at": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StatusChangeRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Obligatorio al cancelar",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "JWT a enviar como \"Authorization: Bearer \u003ctoken\u003e\"",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "Segundos hasta que vence el token de acceso",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "JWT para pedir un nuevo par cuando vence el de acceso",
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "Siempre \"Bearer\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "dentist_id": {
+                    "description": "Dentista del usuario, solo con el rol dentist",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID del usuario",
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "admin, receptionist o dentist",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Permisos de una clave de API, que no tiene rol",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "username": {
+                    "description": "Nombre de usuario",
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserAccount": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "dentist_id": {
+                    "description": "Dentista vinculado, solo con el rol dentist",
+                    "type": "integer"
+                },
+                "failed_logins": {
+                    "description": "Intentos fallidos seguidos",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locked_until": {
+                    "description": "RFC 3339, mientras la cuenta está bloqueada",
+                    "type": "string"
+                },
+                "password_changed_at": {
+                    "description": "RFC 3339",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "admin, receptionist o dentist",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "role",
+                "username"
+            ],
+            "properties": {
+                "dentist_id": {
+                    "description": "Obligatorio con el rol dentist",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "password": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin|receptionist|dentist"
+                    ]
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Clave de API de una integración, creada en /api-keys/. Tiene solo los permisos de sus scopes.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "ruta exige un permiso según el rol del usuario: admin, receptionist o dentist.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,97 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+	"odontology-appointments/internal/web"
+	"odontology-appointments/pkg/models"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GET: Listar claves de API
+// @Summary Listar las claves de API
+// @Description Incluye las revocadas. La clave en sí no se puede consultar; prefix permite reconocerla.
+// @Tags Claves de API
+// @Produce json
+// @Success 200 {array} models.APIKey
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Security BearerAuth
+// @Router /api-keys/ [get]
+func GetAllAPIKeys(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := service.List()
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		web.JSON(w, http.StatusOK, keys)
+	}
+}
+
+// POST: Crear clave de API
+// @Summary Crear una clave de API
+// @Description La clave se muestra solo en esta respuesta: debe guardarse en ese momento.
+// @Description Se envía en el encabezado X-API-Key y tiene solo los permisos indicados en scopes.
+// @Tags Claves de API
+// @Accept json
+// @Produce json
+// @Param clave body models.APIKeyRequest true "Nombre y permisos"
+// @Success 201 {object} models.NewAPIKey
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Router /api-keys/ [post]
+func CreateAPIKey(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request models.APIKeyRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		key, err := service.Create(request)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		web.JSON(w, http.StatusCreated, key)
+	}
+}
+
+// DELETE: Revocar clave de API
+// @Summary Revocar una clave de API
+// @Description La clave deja de autenticar de inmediato y sigue apareciendo en el listado con revoked_at.
+// @Tags Claves de API
+// @Produce json
+// @Param id path int true "ID de la clave"
+// @Success 204
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func RevokeAPIKey(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		if err := service.Revoke(id); err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
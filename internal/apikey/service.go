@@ -0,0 +1,136 @@
+// Package apikey administra las claves de API con las que se autentican las integraciones,
+// como el laboratorio o el envío de recordatorios. Cada clave tiene sus propios permisos y
+// puede revocarse sin afectar a las demás.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"strings"
+	"time"
+)
+
+// keyPrefix identifica las claves de esta API, por ejemplo en un escáner de secretos.
+const keyPrefix = "oak_"
+
+// touchInterval es cada cuánto se actualiza last_used_at como máximo. Actualizarlo en cada
+// pedido convertiría todas las lecturas en escrituras.
+const touchInterval = time.Minute
+
+// prefixLength es cuántos caracteres de la clave se guardan para reconocerla en los listados.
+const prefixLength = len(keyPrefix) + 8
+
+// Service crea, lista, revoca y verifica las claves de API.
+type Service struct {
+	store repository.Store
+}
+
+// NewService crea un servicio de claves de API sobre el almacenamiento indicado.
+func NewService(store repository.Store) *Service {
+	return &Service{store: store}
+}
+
+// List devuelve todas las claves, también las revocadas.
+func (s *Service) List() ([]models.APIKey, error) {
+	return s.store.APIKeys().List()
+}
+
+// Create genera una clave nueva con los permisos pedidos. La clave solo se devuelve acá;
+// después solo queda su hash.
+func (s *Service) Create(request models.APIKeyRequest) (models.NewAPIKey, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if err := validation.Check(request); err != nil {
+		return models.NewAPIKey{}, err
+	}
+	scopes, err := checkScopes(request.Scopes)
+	if err != nil {
+		return models.NewAPIKey{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return models.NewAPIKey{}, err
+	}
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	created := models.NewAPIKey{
+		APIKey: models.APIKey{
+			Name:      request.Name,
+			Prefix:    key[:prefixLength],
+			Scopes:    scopes,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+			Hash:      hash(key),
+		},
+		Key: key,
+	}
+	if err := s.store.APIKeys().Create(&created.APIKey); err != nil {
+		return models.NewAPIKey{}, err
+	}
+	return created, nil
+}
+
+// Revoke revoca la clave. Deja de autenticar de inmediato y se conserva en el listado.
+func (s *Service) Revoke(id int) error {
+	err := s.store.APIKeys().Revoke(id, time.Now().UTC().Format(time.RFC3339))
+	if err == repository.ErrNotFound {
+		return domain.NotFound("API key not found or already revoked")
+	}
+	return err
+}
+
+// Authenticate devuelve el usuario de una clave vigente y registra su uso, a lo sumo una vez
+// por touchInterval. Si no puede registrarlo lo informa en el log y autentica igual. El
+// usuario no tiene rol: sus permisos son los scopes de la clave. Su nombre, que es el actor
+// de la auditoría, incluye el ID de la clave porque el nombre de la clave puede repetirse.
+func (s *Service) Authenticate(key string) (models.User, error) {
+	stored, err := s.store.APIKeys().GetByHash(hash(key))
+	if err == repository.ErrNotFound {
+		return models.User{}, domain.Unauthorized("Invalid API key")
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	now := time.Now().UTC()
+	if last, err := time.Parse(time.RFC3339, stored.LastUsedAt); err != nil || now.Sub(last) >= touchInterval {
+		if err := s.store.APIKeys().Touch(stored.ID, now.Format(time.RFC3339)); err != nil {
+			log.Printf("recording the use of API key %d: %v", stored.ID, err)
+		}
+	}
+	return models.User{ID: stored.ID, Username: fmt.Sprintf("api-key:%d:%s", stored.ID, stored.Name), Scopes: stored.Scopes}, nil
+}
+
+// checkScopes controla que los permisos existan y los devuelve sin repetidos.
+func checkScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, domain.FieldError("scopes", "must contain at least one permission")
+	}
+	unique := make([]string, 0, len(scopes))
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !auth.ValidScope(scope) {
+			return nil, domain.FieldError("scopes", fmt.Sprintf("%q is not a permission that can be granted to an API key", scope))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique, nil
+}
+
+// hash devuelve el SHA-256 de la clave. Las claves son aleatorias y largas, así que no hace
+// falta un hash lento como el de las contraseñas.
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
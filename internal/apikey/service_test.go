@@ -0,0 +1,133 @@
+package apikey
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		want   []string
+		field  string
+	}{
+		{"scopes without duplicates", []string{"appointments:read", " patients:read", "appointments:read"}, []string{"appointments:read", "patients:read"}, ""},
+		{"without scopes", nil, nil, "scopes"},
+		{"unknown scope", []string{"appointments:delete"}, nil, "scopes"},
+		{"admin only scope", []string{"users:admin"}, nil, "scopes"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := memory.New()
+			service := NewService(store)
+
+			created, err := service.Create(models.APIKeyRequest{Name: " lab ", Scopes: test.scopes})
+			if test.field != "" {
+				var invalid *domain.ValidationError
+				if !errors.As(err, &invalid) || invalid.Fields[0].Field != test.field {
+					t.Fatalf("error = %v, want a validation error on %s", err, test.field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(created.Key, keyPrefix) || !strings.HasPrefix(created.Key, created.Prefix) || len(created.Prefix) != prefixLength {
+				t.Errorf("key %q does not start with its prefix %q", created.Key, created.Prefix)
+			}
+			if created.Name != "lab" || !reflect.DeepEqual(created.Scopes, test.want) {
+				t.Errorf("created = %+v, want name lab and scopes %v", created.APIKey, test.want)
+			}
+			stored, err := store.APIKeys().GetByHash(hash(created.Key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Hash == created.Key || strings.Contains(stored.Hash, created.Key[len(keyPrefix):]) {
+				t.Errorf("the key is stored in plain text: %q", stored.Hash)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	store := memory.New()
+	service := NewService(store)
+	created, err := service.Create(models.APIKeyRequest{Name: "lab", Scopes: []string{"appointments:read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Otra clave con el mismo nombre debe distinguirse en la auditoría
+	other, err := service.Create(models.APIKeyRequest{Name: "lab", Scopes: []string{"patients:read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := service.Authenticate(created.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != "" || !reflect.DeepEqual(user.Scopes, []string{"appointments:read"}) {
+		t.Errorf("user = %+v, want only the key scopes", user)
+	}
+	otherUser, err := service.Authenticate(other.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username == otherUser.Username {
+		t.Errorf("keys %d and %d share the actor %q", created.ID, other.ID, user.Username)
+	}
+
+	stored, _ := store.APIKeys().GetByHash(hash(created.Key))
+	if stored.LastUsedAt == "" {
+		t.Fatal("last_used_at was not recorded")
+	}
+	// Dentro de touchInterval no se vuelve a escribir
+	old := time.Now().UTC().Add(-touchInterval / 2).Format(time.RFC3339)
+	if err := store.APIKeys().Touch(created.ID, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(created.Key); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := store.APIKeys().GetByHash(hash(created.Key)); stored.LastUsedAt != old {
+		t.Errorf("last_used_at = %s, want it unchanged at %s", stored.LastUsedAt, old)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"unknown key", keyPrefix + "unknown"},
+		{"key without its last character", created.Key[:len(created.Key)-1]},
+	}
+	for _, test := range tests {
+		if _, err := service.Authenticate(test.key); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Errorf("%s: error = %v, want unauthorized", test.name, err)
+		}
+	}
+
+	if err := service.Revoke(created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(created.Key); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("revoked key: error = %v, want unauthorized", err)
+	}
+	if err := service.Revoke(created.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("second revoke: error = %v, want not found", err)
+	}
+	keys, err := service.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].RevokedAt == "" {
+		t.Errorf("keys = %+v, want the revoked key still listed", keys)
+	}
+}
@@ -0,0 +1,49 @@
+package appointment
+
+import (
+	"net/http"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/security"
+	"odontology-appointments/internal/web"
+)
+
+// CheckAccess controla que un usuario limitado al dentista dentistID pueda ver y modificar el
+// turno. Con dentistID 0 no hay límite.
+func (s *Service) CheckAccess(dentistID, id int) error {
+	if dentistID == 0 {
+		return nil
+	}
+	appointment, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	return checkDentist(dentistID, appointment.DentistID)
+}
+
+// checkDentist controla que un usuario limitado al dentista scope solo use turnos de ese
+// dentista. Un dentistID 0 significa que no se indicó y no se controla.
+func checkDentist(scope, dentistID int) error {
+	if scope != 0 && dentistID != 0 && dentistID != scope {
+		return domain.Forbidden("Only appointments of dentist %d are accessible", scope)
+	}
+	return nil
+}
+
+// allowed responde el error y devuelve false si el usuario del pedido no puede acceder al turno.
+func allowed(w http.ResponseWriter, r *http.Request, service *Service, id int) bool {
+	if err := service.CheckAccess(security.DentistScope(r), id); err != nil {
+		web.Fail(w, err)
+		return false
+	}
+	return true
+}
+
+// allowedDentist responde el error y devuelve false si el usuario del pedido no puede usar
+// turnos del dentista indicado.
+func allowedDentist(w http.ResponseWriter, r *http.Request, dentistID int) bool {
+	if err := checkDentist(security.DentistScope(r), dentistID); err != nil {
+		web.Fail(w, err)
+		return false
+	}
+	return true
+}
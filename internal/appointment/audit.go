@@ -0,0 +1,16 @@
+package appointment
+
+import (
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+// record guarda el cambio de un turno en el registro de auditoría. before es nil en un alta.
+func record(repos repository.Repositories, actor, action string, before *models.Appointment, after models.Appointment) error {
+	var previous interface{}
+	if before != nil {
+		previous = *before
+	}
+	return audit.Record(repos, actor, audit.ResourceAppointment, after.ID, action, previous, after)
+}
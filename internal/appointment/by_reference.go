@@ -0,0 +1,38 @@
+package appointment
+
+import (
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+// CreateByReference crea un turno buscando al paciente por DNI y al dentista por matrícula.
+// Si scope no es 0, el dentista debe ser el de ese ID (ver CheckAccess).
+func (s *Service) CreateByReference(request models.AppointmentByReference, scope int, actor string) (models.Appointment, error) {
+	request.PatientDNI = strings.TrimSpace(request.PatientDNI)
+	request.DentistLicense = strings.TrimSpace(request.DentistLicense)
+	if err := validation.Check(request); err != nil {
+		return models.Appointment{}, err
+	}
+
+	patient, err := s.store.Patients().GetByDNI(request.PatientDNI)
+	if err != nil {
+		return models.Appointment{}, notFound(err, "Patient with DNI "+request.PatientDNI+" not found")
+	}
+	dentist, err := s.store.Dentists().GetByLicense(request.DentistLicense)
+	if err != nil {
+		return models.Appointment{}, notFound(err, "Dentist with license "+request.DentistLicense+" not found")
+	}
+	if err := checkDentist(scope, dentist.ID); err != nil {
+		return models.Appointment{}, err
+	}
+
+	return s.Create(models.Appointment{
+		Date:        request.Date,
+		Time:        request.Time,
+		Duration:    request.Duration,
+		Description: request.Description,
+		PatientID:   patient.ID,
+		DentistID:   dentist.ID,
+	}, actor)
+}
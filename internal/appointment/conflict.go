@@ -0,0 +1,104 @@
+package appointment
+
+import (
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/schedule"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"time"
+)
+
+// findConflict busca un turno del mismo dentista o del mismo paciente que se superponga
+// con el turno recibido. Devuelve el ID del turno en conflicto, o 0 si no hay ninguno.
+// excludeID permite ignorar el propio turno cuando se está actualizando.
+func findConflict(repos repository.Repositories, appointment models.Appointment, excludeID int) (int, error) {
+	slot, err := timeslot.New(appointment.Date, appointment.Time, time.Duration(appointment.Duration)*time.Minute)
+	if err != nil {
+		return 0, err
+	}
+
+	// Se consultan también el día anterior y el siguiente para contemplar turnos que cruzan
+	// la medianoche (la duración máxima es menor a un día)
+	from := slot.Start.AddDate(0, 0, -1).Format(timeslot.DateLayout)
+	to := slot.Start.AddDate(0, 0, 1).Format(timeslot.DateLayout)
+
+	// Los turnos cancelados o a los que el paciente no asistió no ocupan el horario
+	booked, err := repos.Appointments().Booked(appointment.DentistID, appointment.PatientID, from, to, excludeID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, other := range booked {
+		otherSlot, err := timeslot.New(other.Date, other.Time, time.Duration(other.Duration)*time.Minute)
+		if err != nil {
+			// Turnos viejos con formato inválido no pueden compararse
+			continue
+		}
+		if slot.Overlaps(otherSlot) {
+			return other.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// checkBooking aplica los controles de un turno que se reserva o se mueve: que el paciente
+// y el dentista existan, que no se superponga con otro turno y que caiga dentro del horario
+// de atención. excludeID es el propio turno cuando se está modificando.
+func (s *Service) checkBooking(repos repository.Repositories, appointment models.Appointment, excludeID int) error {
+	if err := checkReferences(repos, appointment); err != nil {
+		return err
+	}
+	if err := checkConflict(repos, appointment, excludeID); err != nil {
+		return err
+	}
+	return s.checkWorkingHours(repos, appointment)
+}
+
+// checkConflict devuelve un *domain.OverlapError si el turno se superpone con otro.
+func checkConflict(repos repository.Repositories, appointment models.Appointment, excludeID int) error {
+	conflictID, err := findConflict(repos, appointment, excludeID)
+	if err == timeslot.ErrInvalid {
+		return domain.Invalid("Invalid date or time format")
+	}
+	if err != nil {
+		return err
+	}
+	if conflictID != 0 {
+		return &domain.OverlapError{AppointmentID: conflictID}
+	}
+	return nil
+}
+
+// checkWorkingHours devuelve un error si el turno no cae dentro del horario de atención del
+// dentista.
+func (s *Service) checkWorkingHours(repos repository.Repositories, appointment models.Appointment) error {
+	ok, err := s.withinWorkingHours(repos, appointment)
+	if err == timeslot.ErrInvalid {
+		return domain.Invalid("Invalid date or time format")
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.Unprocessable("Appointment is outside the dentist's working hours")
+	}
+	return nil
+}
+
+// withinWorkingHours indica si el turno cae dentro del horario de atención del dentista. Un
+// dentista sin horarios cargados solo acepta turnos con Options.AllowUnscheduled.
+func (s *Service) withinWorkingHours(repos repository.Repositories, appointment models.Appointment) (bool, error) {
+	slot, err := timeslot.New(appointment.Date, appointment.Time, time.Duration(appointment.Duration)*time.Minute)
+	if err != nil {
+		return false, err
+	}
+	blocks, err := repos.Schedules().Get(appointment.DentistID)
+	if err != nil {
+		return false, err
+	}
+	if len(blocks) == 0 {
+		return s.options.AllowUnscheduled, nil
+	}
+	return schedule.Covers(blocks, slot), nil
+}
@@ -0,0 +1,34 @@
+package appointment
+
+import (
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"time"
+)
+
+const (
+	// DefaultDuration es la duración en minutos que se asigna cuando el turno no la indica.
+	DefaultDuration = 30
+	// MaxDuration es la duración máxima en minutos que puede tener un turno. Se controla con
+	// la regla duration de las etiquetas validate.
+	MaxDuration = models.MaxDuration
+)
+
+// normalizeDuration asigna la duración por defecto si no se indicó. El rango se controla
+// con las reglas de validación del modelo.
+func normalizeDuration(appointment *models.Appointment) {
+	if appointment.Duration == 0 {
+		appointment.Duration = DefaultDuration
+	}
+}
+
+// setEndTime completa la hora de finalización del turno. Si la fecha u hora guardadas
+// no tienen un formato válido se deja vacía.
+func setEndTime(appointment *models.Appointment) {
+	slot, err := timeslot.New(appointment.Date, appointment.Time, time.Duration(appointment.Duration)*time.Minute)
+	if err != nil {
+		appointment.EndTime = ""
+		return
+	}
+	appointment.EndTime = slot.End.Format(timeslot.TimeLayout)
+}
@@ -0,0 +1,78 @@
+package appointment
+
+import (
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+// Expansion indica qué datos relacionados se incluyen en los turnos.
+type Expansion struct {
+	Patient bool
+	Dentist bool
+}
+
+// None indica si no se pidió incluir ningún dato.
+func (e Expansion) None() bool {
+	return !e.Patient && !e.Dentist
+}
+
+// ParseExpansion interpreta el parámetro expand, una lista separada por comas de patient y
+// dentist.
+func ParseExpansion(value string) (Expansion, error) {
+	var expansion Expansion
+	for _, name := range strings.Split(value, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "patient":
+			expansion.Patient = true
+		case "dentist":
+			expansion.Dentist = true
+		default:
+			return expansion, domain.FieldError("expand", "must be a comma-separated list of patient, dentist")
+		}
+	}
+	return expansion, nil
+}
+
+// Expand agrega a cada turno los datos del paciente y del dentista pedidos. Cada paciente y
+// dentista se busca una sola vez aunque aparezca en varios turnos.
+func (s *Service) Expand(appointments []models.Appointment, expansion Expansion) ([]models.ExpandedAppointment, error) {
+	patients := map[int]*models.PatientSummary{}
+	dentists := map[int]*models.DentistSummary{}
+
+	expanded := make([]models.ExpandedAppointment, len(appointments))
+	for i, appointment := range appointments {
+		expanded[i].Appointment = appointment
+		if expansion.Patient {
+			summary, ok := patients[appointment.PatientID]
+			if !ok {
+				patient, err := s.store.Patients().Get(appointment.PatientID)
+				if err != nil && err != repository.ErrNotFound {
+					return nil, err
+				}
+				if err == nil {
+					summary = &models.PatientSummary{ID: patient.ID, LastName: patient.LastName, FirstName: patient.FirstName, DNI: patient.DNI}
+				}
+				patients[appointment.PatientID] = summary
+			}
+			expanded[i].Patient = summary
+		}
+		if expansion.Dentist {
+			summary, ok := dentists[appointment.DentistID]
+			if !ok {
+				dentist, err := s.store.Dentists().Get(appointment.DentistID)
+				if err != nil && err != repository.ErrNotFound {
+					return nil, err
+				}
+				if err == nil {
+					summary = &models.DentistSummary{ID: dentist.ID, LastName: dentist.LastName, FirstName: dentist.FirstName, License: dentist.License}
+				}
+				dentists[appointment.DentistID] = summary
+			}
+			expanded[i].Dentist = summary
+		}
+	}
+	return expanded, nil
+}
@@ -0,0 +1,57 @@
+package appointment
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"strings"
+	"time"
+)
+
+// statuses son los estados por los que se puede filtrar un listado.
+var statuses = []string{StatusScheduled, StatusConfirmed, StatusCompleted, StatusCancelled, StatusNoShow}
+
+// checkFilter valida el filtro y la página de un listado de turnos.
+func checkFilter(filter repository.AppointmentFilter, page repository.Page) error {
+	var errs []models.FieldError
+	if err := page.Check(repository.AppointmentSortFields); err != nil {
+		var invalid *domain.ValidationError
+		if !errors.As(err, &invalid) {
+			return err
+		}
+		errs = append(errs, invalid.Fields...)
+	}
+	if !isDate(filter.DateFrom) {
+		errs = append(errs, models.FieldError{Field: "date_from", Message: "must be a date in YYYY-MM-DD format"})
+	}
+	if !isDate(filter.DateTo) {
+		errs = append(errs, models.FieldError{Field: "date_to", Message: "must be a date in YYYY-MM-DD format"})
+	}
+	if filter.DateFrom != "" && filter.DateTo != "" && filter.DateTo < filter.DateFrom {
+		errs = append(errs, models.FieldError{Field: "date_to", Message: "must not be before date_from"})
+	}
+	if filter.Status != "" && !isStatus(filter.Status) {
+		errs = append(errs, models.FieldError{Field: "status", Message: "must be one of " + strings.Join(statuses, ", ")})
+	}
+	if len(errs) > 0 {
+		return &domain.ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+func isStatus(value string) bool {
+	for _, status := range statuses {
+		if status == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isDate indica si value está vacío o es una fecha YYYY-MM-DD.
+func isDate(value string) bool {
+	_, err := time.Parse(timeslot.DateLayout, value)
+	return value == "" || err == nil
+}
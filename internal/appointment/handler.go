@@ -1,9 +1,13 @@
 package appointment
 
 import (
-	"database/sql"
 	"encoding/json"
+	"io"
 	"net/http"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/security"
+	"odontology-appointments/internal/web"
 	"odontology-appointments/pkg/models"
 	"strconv"
 
@@ -12,29 +16,173 @@ import (
 
 // GET: Obtener todos los turnos
 // @Summary Listar todos los turnos
+// @Description Devuelve una página de turnos. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.
 // @Tags Turno
 // @Produce json
-// @Success 200 {array} models.Appointment
+// @Param dentist_id query int false "ID del dentista"
+// @Param patient_id query int false "ID del paciente"
+// @Param date_from query string false "Fecha desde, inclusive (YYYY-MM-DD)"
+// @Param date_to query string false "Fecha hasta, inclusive (YYYY-MM-DD)"
+// @Param status query string false "Estado: scheduled, confirmed, completed, cancelled o no_show"
+// @Param expand query string false "Datos a incluir en cada turno: patient, dentist"
+// @Param include_deleted query bool false "Incluir los turnos eliminados (requiere el permiso appointments:admin)"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Param sort query string false "Campos de orden separados por comas, con - para orden descendente: id, date, time, duration, status, patient_id, dentist_id"
+// @Success 200 {array} models.ExpandedAppointment
+// @Header 200 {integer} X-Total-Count "Total de registros sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /turnos [get]
-func GetAllAppointments(db *sql.DB) http.HandlerFunc {
+func GetAllAppointments(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		rows, err := db.Query("SELECT * FROM appointments")
+		listAppointments(w, r, service, service.List)
+	}
+}
+
+// GET: Turnos de un paciente
+// @Summary Listar los turnos de un paciente
+// @Description Devuelve una página de los turnos del paciente. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.
+// @Tags Paciente
+// @Produce json
+// @Param id path int true "ID del paciente"
+// @Param dentist_id query int false "ID del dentista"
+// @Param date_from query string false "Fecha desde, inclusive (YYYY-MM-DD)"
+// @Param date_to query string false "Fecha hasta, inclusive (YYYY-MM-DD)"
+// @Param status query string false "Estado: scheduled, confirmed, completed, cancelled o no_show"
+// @Param expand query string false "Datos a incluir en cada turno: patient, dentist"
+// @Param include_deleted query bool false "Incluir los turnos eliminados (requiere el permiso appointments:admin)"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Param sort query string false "Campos de orden separados por comas, con - para orden descendente: id, date, time, duration, status, patient_id, dentist_id"
+// @Success 200 {array} models.ExpandedAppointment
+// @Header 200 {integer} X-Total-Count "Total de registros sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /patients/{id}/appointments [get]
+func GetPatientAppointments(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["id"])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
-		defer rows.Close()
+		listAppointments(w, r, service, func(filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+			return service.ListForPatient(id, filter, page)
+		})
+	}
+}
 
-		var appointments []models.Appointment
-		for rows.Next() {
-			var appointment models.Appointment
-			rows.Scan(&appointment.ID, &appointment.Date, &appointment.Time, &appointment.Description, &appointment.PatientID, &appointment.DentistID)
-			appointments = append(appointments, appointment)
+// GET: Turnos de un dentista
+// @Summary Listar los turnos de un dentista
+// @Description Devuelve una página de los turnos del dentista. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.
+// @Tags Dentista
+// @Produce json
+// @Param id path int true "ID del dentista"
+// @Param patient_id query int false "ID del paciente"
+// @Param date_from query string false "Fecha desde, inclusive (YYYY-MM-DD)"
+// @Param date_to query string false "Fecha hasta, inclusive (YYYY-MM-DD)"
+// @Param status query string false "Estado: scheduled, confirmed, completed, cancelled o no_show"
+// @Param expand query string false "Datos a incluir en cada turno: patient, dentist"
+// @Param include_deleted query bool false "Incluir los turnos eliminados (requiere el permiso appointments:admin)"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Param sort query string false "Campos de orden separados por comas, con - para orden descendente: id, date, time, duration, status, patient_id, dentist_id"
+// @Success 200 {array} models.ExpandedAppointment
+// @Header 200 {integer} X-Total-Count "Total de registros sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /dentists/{id}/appointments [get]
+func GetDentistAppointments(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(mux.Vars(r)["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
 		}
+		if !allowedDentist(w, r, id) {
+			return
+		}
+		listAppointments(w, r, service, func(filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+			return service.ListForDentist(id, filter, page)
+		})
+	}
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(appointments)
+// listAppointments responde una página de turnos según los filtros, la página y el expand de
+// la query. list obtiene los turnos.
+func listAppointments(w http.ResponseWriter, r *http.Request, service *Service,
+	list func(repository.AppointmentFilter, repository.Page) ([]models.Appointment, int, error)) {
+	page, err := web.PageQuery(r)
+	if err != nil {
+		web.Fail(w, err)
+		return
 	}
+	filter, err := filterQuery(r)
+	if err != nil {
+		web.Fail(w, err)
+		return
+	}
+	// Un dentista solo ve sus propios turnos
+	if !allowedDentist(w, r, filter.DentistID) {
+		return
+	}
+	if scope := security.DentistScope(r); scope != 0 {
+		filter.DentistID = scope
+	}
+	includeDeleted, ok := security.IncludeDeleted(w, r, auth.AppointmentsAdmin)
+	if !ok {
+		return
+	}
+	filter.IncludeDeleted = includeDeleted
+	expansion, err := ParseExpansion(r.URL.Query().Get("expand"))
+	if err != nil {
+		web.Fail(w, err)
+		return
+	}
+
+	appointments, total, err := list(filter, page)
+	if err != nil {
+		web.Fail(w, err)
+		return
+	}
+	if expansion.None() {
+		web.List(w, r, page, total, appointments)
+		return
+	}
+	expanded, err := service.Expand(appointments, expansion)
+	if err != nil {
+		web.Fail(w, err)
+		return
+	}
+	web.List(w, r, page, total, expanded)
+}
+
+// filterQuery lee de la query los filtros del listado de turnos.
+func filterQuery(r *http.Request) (repository.AppointmentFilter, error) {
+	query := r.URL.Query()
+	filter := repository.AppointmentFilter{
+		DateFrom: query.Get("date_from"),
+		DateTo:   query.Get("date_to"),
+		Status:   query.Get("status"),
+	}
+	var err error
+	if filter.DentistID, err = web.QueryInt(r, "dentist_id"); err != nil {
+		return filter, err
+	}
+	filter.PatientID, err = web.QueryInt(r, "patient_id")
+	return filter, err
 }
 
 // POST: Crear un nuevo turno
@@ -44,29 +192,30 @@ func GetAllAppointments(db *sql.DB) http.HandlerFunc {
 // @Produce json
 // @Param turno body models.Appointment true "Turno"
 // @Success 201 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 409 {object} models.ConflictError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /turnos [post]
-func CreateAppointment(db *sql.DB) http.HandlerFunc {
+func CreateAppointment(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var appointment models.Appointment
 		err := json.NewDecoder(r.Body).Decode(&appointment)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			web.DecodeError(w, err)
 			return
 		}
-
-		stmt, err := db.Prepare("INSERT INTO appointments (date, time, description, patient_id, dentist_id) VALUES (?, ?, ?, ?, ?)")
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if !allowedDentist(w, r, appointment.DentistID) {
 			return
 		}
-		res, err := stmt.Exec(appointment.Date, appointment.Time, appointment.Description, appointment.PatientID, appointment.DentistID)
+
+		appointment, err = service.Create(appointment, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		id, _ := res.LastInsertId()
-		appointment.ID = int(id)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(appointment)
 	}
@@ -77,70 +226,94 @@ func CreateAppointment(db *sql.DB) http.HandlerFunc {
 // @Tags Turno
 // @Produce json
 // @Param id path int true "ID del turno"
-// @Success 200 {object} models.Appointment
+// @Param expand query string false "Datos a incluir en el turno: patient, dentist"
+// @Success 200 {object} models.ExpandedAppointment
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /turnos/{id} [get]
-func GetAppointmentByID(db *sql.DB) http.HandlerFunc {
+func GetAppointmentByID(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		var appointment models.Appointment
-		err = db.QueryRow("SELECT * FROM appointments WHERE id = ?", id).Scan(
-			&appointment.ID, &appointment.Date, &appointment.Time, &appointment.Description, &appointment.PatientID, &appointment.DentistID)
+		expansion, err := ParseExpansion(r.URL.Query().Get("expand"))
 		if err != nil {
-			if err == sql.ErrNoRows {
-				http.Error(w, "Appointment not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			web.Fail(w, err)
+			return
+		}
+
+		appointment, err := service.Get(id)
+		if err != nil {
+			web.Fail(w, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(appointment)
+		if expansion.None() {
+			json.NewEncoder(w).Encode(appointment)
+			return
+		}
+		expanded, err := service.Expand([]models.Appointment{appointment}, expansion)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		json.NewEncoder(w).Encode(expanded[0])
 	}
 }
 
 // PUT: Actualizar turno
 // @Summary Actualizar un turno
+// @Description La fecha, la hora, la duración y el dentista deben ser los actuales: para moverlo
+// @Description se usa POST /appointments/{id}/reschedule, que guarda el horario anterior.
 // @Tags Turno
 // @Accept json
 // @Produce json
 // @Param id path int true "ID del turno"
 // @Param turno body models.Appointment true "Turno"
 // @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.ConflictError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /turnos/{id} [put]
-func UpdateAppointment(db *sql.DB) http.HandlerFunc {
+func UpdateAppointment(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
 			return
 		}
 
 		var appointment models.Appointment
 		err = json.NewDecoder(r.Body).Decode(&appointment)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			web.DecodeError(w, err)
 			return
 		}
-
-		stmt, err := db.Prepare("UPDATE appointments SET date = ?, time = ?, description = ?, patient_id = ?, dentist_id = ? WHERE id = ?")
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if !allowedDentist(w, r, appointment.DentistID) {
 			return
 		}
 
-		_, err = stmt.Exec(appointment.Date, appointment.Time, appointment.Description, appointment.PatientID, appointment.DentistID, id)
+		appointment, err = service.Update(id, appointment, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
@@ -151,125 +324,494 @@ func UpdateAppointment(db *sql.DB) http.HandlerFunc {
 
 // PATCH: Actualizar parcialmente turno
 // @Summary Actualizar algunos campos de un turno
+// @Description No puede cambiar la fecha, la hora, la duración ni el dentista: para moverlo se
+// @Description usa POST /appointments/{id}/reschedule, que guarda el horario anterior.
 // @Tags Turno
 // @Accept json
 // @Produce json
 // @Param id path int true "ID del turno"
 // @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.ConflictError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /turnos/{id} [patch]
-func PartialUpdateAppointment(db *sql.DB) http.HandlerFunc {
+func PartialUpdateAppointment(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		var fields map[string]interface{}
-		err = json.NewDecoder(r.Body).Decode(&fields)
+		// Los campos recibidos se aplican sobre el turno actual y se valida el resultado
+		_, err = service.Patch(id, func(appointment *models.Appointment) error {
+			if err := web.Decode(r, appointment); err != nil {
+				return err
+			}
+			return checkDentist(security.DentistScope(r), appointment.DentistID)
+		}, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			web.Fail(w, err)
 			return
 		}
 
-		query := "UPDATE appointments SET "
-		args := []interface{}{}
-		first := true
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
 
-		if date, ok := fields["date"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "date = ?"
-			args = append(args, date)
-			first = false
+// DELETE: Eliminar turno
+// @Summary Eliminar un turno
+// @Description Es una baja lógica: el turno deja de aparecer en los listados, conserva sus historiales y puede restaurarse.
+// @Tags Turno
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Success 204
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /turnos/{id} [delete]
+func DeleteAppointment(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
+			return
 		}
 
-		if time, ok := fields["time"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "time = ?"
-			args = append(args, time)
-			first = false
+		if err := service.Delete(id, security.Actor(r)); err != nil {
+			web.Fail(w, err)
+			return
 		}
 
-		if description, ok := fields["description"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "description = ?"
-			args = append(args, description)
-			first = false
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// POST: Restaurar turno
+// @Summary Restaurar un turno eliminado
+// @Description El paciente y el dentista deben estar activos y el turno no debe superponerse con otro.
+// @Tags Turno
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /turnos/{id}/restore [post]
+func RestoreAppointment(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
 		}
 
-		if patientID, ok := fields["patient_id"].(float64); ok {
-			if !first {
-				query += ", "
-			}
-			query += "patient_id = ?"
-			args = append(args, int(patientID))
-			first = false
+		appointment, err := service.Restore(id, security.Actor(r))
+		if err != nil {
+			web.Fail(w, err)
+			return
 		}
 
-		if dentistID, ok := fields["dentist_id"].(float64); ok {
-			if !first {
-				query += ", "
-			}
-			query += "dentist_id = ?"
-			args = append(args, int(dentistID))
+		web.JSON(w, http.StatusOK, appointment)
+	}
+}
+
+// POST: Crear un turno por DNI del paciente y matrícula del dentista
+// @Summary Agregar un nuevo turno usando DNI del paciente y matrícula del dentista
+// @Tags Turno
+// @Accept json
+// @Produce json
+// @Param turno body models.AppointmentByReference true "Turno"
+// @Success 201 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.ConflictError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/by-reference [post]
+func CreateAppointmentByReference(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request models.AppointmentByReference
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		appointment, err := service.CreateByReference(request, security.DentistScope(r), security.Actor(r))
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(appointment)
+	}
+}
+
+// POST: Confirmar turno
+// @Summary Confirmar un turno
+// @Tags Turno
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Param cambio body models.StatusChangeRequest false "Motivo"
+// @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/confirm [post]
+func ConfirmAppointment(service *Service) http.HandlerFunc {
+	return changeStatus(service, StatusConfirmed)
+}
+
+// POST: Cancelar turno
+// @Summary Cancelar un turno indicando el motivo
+// @Tags Turno
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Param cambio body models.StatusChangeRequest true "Motivo"
+// @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/cancel [post]
+func CancelAppointment(service *Service) http.HandlerFunc {
+	return changeStatus(service, StatusCancelled)
+}
+
+// POST: Completar turno
+// @Summary Marcar un turno como completado
+// @Tags Turno
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Param cambio body models.StatusChangeRequest false "Motivo"
+// @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/complete [post]
+func CompleteAppointment(service *Service) http.HandlerFunc {
+	return changeStatus(service, StatusCompleted)
+}
+
+// POST: Marcar ausencia
+// @Summary Marcar que el paciente no se presentó al turno
+// @Tags Turno
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Param cambio body models.StatusChangeRequest false "Motivo"
+// @Success 200 {object} models.Appointment
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/no-show [post]
+func NoShowAppointment(service *Service) http.HandlerFunc {
+	return changeStatus(service, StatusNoShow)
+}
+
+// changeStatus arma el handler que lleva un turno al estado indicado.
+func changeStatus(service *Service, to string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
+			return
 		}
 
-		query += " WHERE id = ?"
-		args = append(args, id)
+		// El cuerpo es opcional; el servicio exige el motivo cuando corresponde
+		var request models.StatusChangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+			web.DecodeError(w, err)
+			return
+		}
 
-		stmt, err := db.Prepare(query)
+		appointment, err := service.ChangeStatus(id, to, request.Reason, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		_, err = stmt.Exec(args...)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(appointment)
+	}
+}
+
+// GET: Historial de estados de un turno
+// @Summary Obtener el historial de cambios de estado de un turno
+// @Tags Turno
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Success 200 {array} models.StatusChange
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/status-history [get]
+func GetStatusHistory(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		changes, err := service.StatusHistory(id)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(changes)
 	}
 }
 
-// DELETE: Eliminar turno
-// @Summary Eliminar un turno
+// POST: Reprogramar turno
+// @Summary Mover un turno a otra fecha, hora o dentista conservando el historial
 // @Tags Turno
+// @Accept json
 // @Produce json
 // @Param id path int true "ID del turno"
-// @Success 204
+// @Param reprogramacion body models.RescheduleRequest true "Nuevo horario"
+// @Success 200 {object} models.Appointment
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
-// @Router /turnos/{id} [delete]
-func DeleteAppointment(db *sql.DB) http.HandlerFunc {
+// @Failure 409 {object} models.ConflictError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/reschedule [post]
+func RescheduleAppointment(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		stmt, err := db.Prepare("DELETE FROM appointments WHERE id = ?")
+		var request models.RescheduleRequest
+		err = json.NewDecoder(r.Body).Decode(&request)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.DecodeError(w, err)
+			return
+		}
+		if !allowedDentist(w, r, request.DentistID) {
 			return
 		}
 
-		_, err = stmt.Exec(id)
+		appointment, err := service.Reschedule(id, request, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(appointment)
+	}
+}
+
+// GET: Historial de reprogramaciones de un turno
+// @Summary Obtener los horarios anteriores de un turno reprogramado
+// @Tags Turno
+// @Produce json
+// @Param id path int true "ID del turno"
+// @Success 200 {array} models.Reschedule
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointments/{id}/reschedules [get]
+func GetRescheduleHistory(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
+			return
+		}
+
+		history, err := service.RescheduleHistory(id)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
+	}
+}
+
+// POST: Crear una serie de turnos
+// @Summary Crear una serie de turnos recurrentes
+// @Description Genera un turno por cada ocurrencia de la serie. Las ocurrencias que se superponen
+// @Description con otros turnos o caen fuera del horario del dentista se omiten y se informan.
+// @Tags Serie
+// @Accept json
+// @Produce json
+// @Param serie body models.AppointmentSeries true "Serie"
+// @Success 201 {object} models.SeriesResult
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointment-series/ [post]
+func CreateSeries(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var series models.AppointmentSeries
+		err := json.NewDecoder(r.Body).Decode(&series)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+		if !allowedDentist(w, r, series.DentistID) {
+			return
+		}
+
+		result, err := service.CreateSeries(series, security.Actor(r))
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		web.JSON(w, http.StatusCreated, result)
+	}
+}
+
+// GET: Obtener una serie de turnos
+// @Summary Obtener una serie de turnos con sus ocurrencias
+// @Tags Serie
+// @Produce json
+// @Param id path int true "ID de la serie"
+// @Success 200 {object} models.AppointmentSeries
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointment-series/{id} [get]
+func GetSeriesByID(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		series, err := service.GetSeries(id)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		if !allowedDentist(w, r, series.DentistID) {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(series)
+	}
+}
+
+// PATCH: Modificar turnos de una serie
+// @Summary Modificar un turno de una serie, ese y los siguientes, o toda la serie
+// @Description Cambia hora, duración, dentista o descripción. Los turnos que ya no están
+// @Description pendientes o que quedarían en conflicto se omiten y se informan. Los turnos que
+// @Description cambian de horario o dentista quedan en su historial de reprogramaciones con el motivo indicado.
+// @Tags Serie
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la serie"
+// @Param appointmentId path int true "ID del turno de la serie"
+// @Param scope query string false "this (por defecto), following o all"
+// @Param cambios body models.SeriesUpdate true "Cambios"
+// @Success 200 {object} models.SeriesResult
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /appointment-series/{id}/appointments/{appointmentId} [patch]
+func UpdateSeriesAppointments(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		appointmentID, err := strconv.Atoi(params["appointmentId"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid appointment ID")
+			return
+		}
+
+		if !allowed(w, r, service, appointmentID) {
+			return
+		}
+
+		var changes models.SeriesUpdate
+		err = json.NewDecoder(r.Body).Decode(&changes)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+		if !allowedDentist(w, r, changes.DentistID) {
+			return
+		}
+
+		result, err := service.UpdateSeries(id, appointmentID, r.URL.Query().Get("scope"), changes, security.Actor(r))
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
 	}
 }
@@ -0,0 +1,193 @@
+package appointment
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/pkg/models"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// Usuarios de las pruebas de handlers.
+var (
+	receptionist = models.User{Username: "reception", Role: auth.RoleReceptionist}
+	dentistOne   = models.User{Username: "doctor", Role: auth.RoleDentist, DentistID: 1}
+)
+
+// newTestRouter arma las rutas de turnos que usan las pruebas, sin autenticación: el usuario
+// se agrega al contexto de cada pedido.
+func newTestRouter(service *Service) *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/appointments/", CreateAppointment(service)).Methods("POST")
+	r.HandleFunc("/appointments/{id}", PartialUpdateAppointment(service)).Methods("PATCH")
+	r.HandleFunc("/appointments/{id}/cancel", CancelAppointment(service)).Methods("POST")
+	r.HandleFunc("/appointments/{id}/reschedule", RescheduleAppointment(service)).Methods("POST")
+	r.HandleFunc("/appointments/{id}/reschedules", GetRescheduleHistory(service)).Methods("GET")
+	r.HandleFunc("/appointment-series/{id}/appointments/{appointmentId}", UpdateSeriesAppointments(service)).Methods("PATCH")
+	return r
+}
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, service *Service)
+		user   models.User
+		method string
+		path   string
+		body   string
+		status int
+		// check controla el cuerpo de la respuesta, si se indica.
+		check func(t *testing.T, body []byte)
+	}{
+		{
+			name: "create", user: receptionist, method: "POST", path: "/appointments/",
+			body:   `{"date":"2030-01-07","time":"10:00","duration":30,"patient_id":2,"dentist_id":2}`,
+			status: http.StatusOK,
+		},
+		{
+			name: "create overlapping", user: receptionist, method: "POST", path: "/appointments/",
+			body:   `{"date":"2030-01-07","time":"09:15","patient_id":2,"dentist_id":1}`,
+			status: http.StatusConflict,
+			check: func(t *testing.T, body []byte) {
+				var conflict models.ConflictError
+				mustDo(t, json.Unmarshal(body, &conflict))
+				if conflict.ConflictingAppointmentID != 1 {
+					t.Errorf("conflicting_appointment_id = %d, want 1", conflict.ConflictingAppointmentID)
+				}
+			},
+		},
+		{
+			name: "create outside working hours", user: receptionist, method: "POST", path: "/appointments/",
+			body:   `{"date":"2030-01-08","time":"10:00","patient_id":2,"dentist_id":1}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "create for a dentist without schedule", user: receptionist, method: "POST", path: "/appointments/",
+			body:   `{"date":"2030-01-07","time":"10:00","patient_id":2,"dentist_id":3}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "create for another dentist", user: dentistOne, method: "POST", path: "/appointments/",
+			body:   `{"date":"2030-01-07","time":"10:00","patient_id":2,"dentist_id":2}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "create with malformed body", user: receptionist, method: "POST", path: "/appointments/",
+			body:   `{"date":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "patch description", user: receptionist, method: "PATCH", path: "/appointments/1",
+			body:   `{"description":"control"}`,
+			status: http.StatusNoContent,
+		},
+		{
+			name: "patch time", user: receptionist, method: "PATCH", path: "/appointments/1",
+			body:   `{"time":"10:00"}`,
+			status: http.StatusUnprocessableEntity,
+			check: func(t *testing.T, body []byte) {
+				if !strings.Contains(string(body), "/appointments/1/reschedule") {
+					t.Errorf("response does not point to the reschedule endpoint: %s", body)
+				}
+			},
+		},
+		{
+			name: "cancel without reason", user: receptionist, method: "POST", path: "/appointments/1/cancel",
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "cancel", user: receptionist, method: "POST", path: "/appointments/1/cancel",
+			body:   `{"reason":"patient called"}`,
+			status: http.StatusOK,
+		},
+		{
+			name: "cancel twice", user: receptionist, method: "POST", path: "/appointments/1/cancel",
+			setup: func(t *testing.T, service *Service) {
+				_, err := service.ChangeStatus(1, StatusCancelled, "first", "test")
+				mustDo(t, err)
+			},
+			body:   `{"reason":"again"}`,
+			status: http.StatusConflict,
+		},
+		{
+			name: "cancel another dentist's appointment", user: models.User{Username: "other", Role: auth.RoleDentist, DentistID: 2},
+			method: "POST", path: "/appointments/1/cancel",
+			body:   `{"reason":"not mine"}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "reschedule", user: receptionist, method: "POST", path: "/appointments/1/reschedule",
+			body:   `{"date":"2030-01-07","time":"12:00","reason":"patient asked"}`,
+			status: http.StatusOK,
+		},
+		{
+			name: "reschedule history", user: receptionist, method: "GET", path: "/appointments/1/reschedules",
+			setup: func(t *testing.T, service *Service) {
+				_, err := service.Reschedule(1, models.RescheduleRequest{Date: monday, Time: "12:00"}, "test")
+				mustDo(t, err)
+			},
+			status: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var history []models.Reschedule
+				mustDo(t, json.Unmarshal(body, &history))
+				if len(history) != 1 || history[0].PreviousTime != "09:00" || history[0].NewTime != "12:00" {
+					t.Errorf("history = %+v, want one move from 09:00 to 12:00", history)
+				}
+			},
+		},
+		{
+			name: "update following occurrences of a series", user: receptionist, method: "PATCH",
+			path: "/appointment-series/1/appointments/3?scope=following",
+			setup: func(t *testing.T, service *Service) {
+				_, err := service.CreateSeries(models.AppointmentSeries{PatientID: 2, DentistID: 2, StartDate: monday,
+					Time: "09:00", Duration: 30, Frequency: "weekly", Count: 3}, "test")
+				mustDo(t, err)
+			},
+			body:   `{"time":"10:00"}`,
+			status: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var result models.SeriesResult
+				mustDo(t, json.Unmarshal(body, &result))
+				if len(result.Appointments) != 2 {
+					t.Errorf("updated %d appointments, want 2", len(result.Appointments))
+				}
+			},
+		},
+		{
+			name: "update a series with an unknown scope", user: receptionist, method: "PATCH",
+			path: "/appointment-series/1/appointments/2?scope=some",
+			setup: func(t *testing.T, service *Service) {
+				_, err := service.CreateSeries(models.AppointmentSeries{PatientID: 2, DentistID: 2, StartDate: monday,
+					Time: "09:00", Duration: 30, Frequency: "weekly", Count: 2}, "test")
+				mustDo(t, err)
+			},
+			body:   `{"time":"10:00"}`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			book(t, service, monday, "09:00", 1, 1)
+			if test.setup != nil {
+				test.setup(t, service)
+			}
+
+			request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			request = request.WithContext(auth.WithUser(request.Context(), test.user))
+			response := httptest.NewRecorder()
+			newTestRouter(service).ServeHTTP(response, request)
+
+			if response.Code != test.status {
+				t.Fatalf("status = %d, want %d: %s", response.Code, test.status, response.Body)
+			}
+			if test.check != nil {
+				test.check(t, response.Body.Bytes())
+			}
+		})
+	}
+}
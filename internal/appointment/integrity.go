@@ -0,0 +1,172 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+)
+
+// Políticas para los turnos de un dentista o paciente que se elimina.
+const (
+	// PolicyRestrict rechaza la eliminación si existen turnos asociados.
+	PolicyRestrict = "restrict"
+	// PolicyCascade elimina los turnos asociados, que se restauran junto con el registro.
+	PolicyCascade = "cascade"
+	// PolicyReassign pasa los turnos a otro dentista (solo para dentistas).
+	PolicyReassign = "reassign"
+)
+
+// checkReferences verifica que el paciente y el dentista del turno existan. Si alguno no
+// existe devuelve un *domain.ValidationError sobre el campo correspondiente.
+func checkReferences(repos repository.Repositories, appointment models.Appointment) error {
+	if _, err := repos.Patients().Get(appointment.PatientID); err != nil {
+		if err == repository.ErrNotFound {
+			return domain.FieldError("patient_id", fmt.Sprintf("Patient %d does not exist", appointment.PatientID))
+		}
+		return err
+	}
+	if _, err := repos.Dentists().Get(appointment.DentistID); err != nil {
+		if err == repository.ErrNotFound {
+			return domain.FieldError("dentist_id", fmt.Sprintf("Dentist %d does not exist", appointment.DentistID))
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteAll da de baja los turnos que cumplen el filtro con la fecha deletedAt. Usar la
+// misma fecha que el dentista o paciente eliminado permite restaurarlos junto con él. Cada
+// baja se registra en la auditoría a nombre de actor.
+func DeleteAll(repos repository.Repositories, filter repository.AppointmentFilter, deletedAt, actor string) error {
+	appointments, err := repos.Appointments().List(filter)
+	if err != nil {
+		return err
+	}
+	for _, appointment := range appointments {
+		if err := deleteAppointment(repos, appointment, deletedAt, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreAll vuelve a activar los turnos eliminados que cumplen el filtro y cuyo paciente y
+// dentista están activos; el resto sigue eliminado. Si alguno se superpone con otro turno
+// devuelve un *domain.OverlapError y quien llama debe descartar la transacción.
+func RestoreAll(repos repository.Repositories, filter repository.AppointmentFilter, actor string) error {
+	appointments, err := repos.Appointments().List(filter)
+	if err != nil {
+		return err
+	}
+	for _, appointment := range appointments {
+		err := restore(repos, appointment, actor)
+		// Un paciente o dentista eliminado deja el turno como está
+		var inactive *domain.Error
+		if errors.As(err, &inactive) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restore vuelve a activar un turno eliminado. Devuelve un error de dominio si su paciente o
+// su dentista están eliminados o si el turno ocupa un horario que ya se reservó.
+func restore(repos repository.Repositories, appointment models.Appointment, actor string) error {
+	if _, err := repos.Patients().Get(appointment.PatientID); err != nil {
+		if err == repository.ErrNotFound {
+			return domain.Conflict("Patient %d is deleted, restore it first", appointment.PatientID)
+		}
+		return err
+	}
+	if _, err := repos.Dentists().Get(appointment.DentistID); err != nil {
+		if err == repository.ErrNotFound {
+			return domain.Conflict("Dentist %d is deleted, restore it first", appointment.DentistID)
+		}
+		return err
+	}
+	if appointment.Status != StatusCancelled && appointment.Status != StatusNoShow {
+		// Los turnos con fecha u hora inválidas no pueden compararse y se restauran igual
+		conflictID, err := findConflict(repos, appointment, appointment.ID)
+		if err != nil && err != timeslot.ErrInvalid {
+			return err
+		}
+		if conflictID != 0 {
+			return &domain.OverlapError{AppointmentID: conflictID}
+		}
+	}
+	if err := repos.Appointments().Restore(appointment.ID); err != nil {
+		return err
+	}
+	restored := appointment
+	restored.DeletedAt = ""
+	return record(repos, actor, audit.ActionRestore, &appointment, restored)
+}
+
+// Open devuelve los turnos que cumplen el filtro y todavía no llegaron a un estado final,
+// es decir los reservados y los confirmados.
+func Open(repos repository.Repositories, filter repository.AppointmentFilter) ([]models.Appointment, error) {
+	appointments, err := repos.Appointments().List(filter)
+	if err != nil {
+		return nil, err
+	}
+	open := appointments[:0]
+	for _, appointment := range appointments {
+		if _, ok := transitions[appointment.Status]; ok {
+			open = append(open, appointment)
+		}
+	}
+	return open, nil
+}
+
+// ReassignDentist pasa los turnos reservados o confirmados y las series de un dentista a
+// otro. Los turnos en un estado final quedan con el dentista original como parte de su
+// historia. Cada turno pasa por los mismos controles que una reserva; si alguno no los
+// cumple devuelve el error y quien llama debe descartar la transacción. repos es la
+// transacción de quien llama.
+func (s *Service) ReassignDentist(repos repository.Repositories, fromID, toID int, actor string) error {
+	appointments, err := Open(repos, repository.AppointmentFilter{DentistID: fromID})
+	if err != nil {
+		return err
+	}
+
+	for _, previous := range appointments {
+		appointment := previous
+		appointment.DentistID = toID
+		if err := s.checkBooking(repos, appointment, appointment.ID); err != nil {
+			var failed *domain.Error
+			if errors.As(err, &failed) {
+				return domain.Conflict("Appointment %d cannot be reassigned: %s", appointment.ID, failed.Message)
+			}
+			return err
+		}
+		if err := repos.Appointments().Update(appointment); err != nil {
+			return err
+		}
+		if err := record(repos, actor, audit.ActionUpdate, &previous, appointment); err != nil {
+			return err
+		}
+	}
+
+	list, err := repos.Series().List(repository.SeriesFilter{DentistID: fromID})
+	if err != nil {
+		return err
+	}
+	for _, previous := range list {
+		series := previous
+		series.DentistID = toID
+		if err := repos.Series().Update(series); err != nil {
+			return err
+		}
+		if err := audit.Record(repos, actor, audit.ResourceSeries, series.ID, audit.ActionUpdate, previous, series); err != nil {
+			return err
+		}
+	}
+	return nil
+}
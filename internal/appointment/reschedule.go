@@ -0,0 +1,96 @@
+package appointment
+
+import (
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"strings"
+	"time"
+)
+
+// Reschedule mueve un turno pendiente a otra fecha, hora o dentista con los mismos controles
+// que al crearlo, y guarda el horario anterior en el historial.
+func (s *Service) Reschedule(id int, request models.RescheduleRequest, actor string) (models.Appointment, error) {
+	if err := validation.Check(request); err != nil {
+		return models.Appointment{}, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	defer tx.Rollback()
+
+	previous, err := findAppointment(tx, id)
+	if err != nil {
+		return previous, notFound(err, "Appointment not found")
+	}
+	if previous.Status != StatusScheduled && previous.Status != StatusConfirmed {
+		return previous, domain.Conflict("Cannot reschedule a %s appointment", previous.Status)
+	}
+
+	appointment := previous
+	appointment.Date = request.Date
+	appointment.Time = request.Time
+	if request.Duration != 0 {
+		appointment.Duration = request.Duration
+	}
+	if request.DentistID != 0 {
+		appointment.DentistID = request.DentistID
+	}
+
+	if err := s.checkBooking(tx, appointment, id); err != nil {
+		return previous, err
+	}
+	if err := tx.Appointments().Update(appointment); err != nil {
+		return previous, err
+	}
+
+	if err := addReschedule(tx, previous, appointment, request.Reason, actor); err != nil {
+		return previous, err
+	}
+	if err := record(tx, actor, audit.ActionReschedule, &previous, appointment); err != nil {
+		return previous, err
+	}
+	if err := tx.Commit(); err != nil {
+		return previous, err
+	}
+
+	setEndTime(&appointment)
+	return appointment, nil
+}
+
+// addReschedule guarda en el historial el horario anterior de un turno que se movió.
+func addReschedule(repos repository.Repositories, previous, appointment models.Appointment, reason, actor string) error {
+	entry := models.Reschedule{
+		AppointmentID:     appointment.ID,
+		PreviousDate:      previous.Date,
+		PreviousTime:      previous.Time,
+		PreviousDuration:  previous.Duration,
+		PreviousDentistID: previous.DentistID,
+		NewDate:           appointment.Date,
+		NewTime:           appointment.Time,
+		NewDuration:       appointment.Duration,
+		NewDentistID:      appointment.DentistID,
+		Reason:            strings.TrimSpace(reason),
+		ChangedBy:         actor,
+		ChangedAt:         time.Now().UTC().Format(time.RFC3339),
+	}
+	return repos.Appointments().AddReschedule(&entry)
+}
+
+// moved indica si el turno cambió de fecha, hora, duración o dentista.
+func moved(previous, appointment models.Appointment) bool {
+	return previous.Date != appointment.Date || previous.Time != appointment.Time ||
+		previous.Duration != appointment.Duration || previous.DentistID != appointment.DentistID
+}
+
+// RescheduleHistory devuelve los horarios anteriores de un turno.
+func (s *Service) RescheduleHistory(id int) ([]models.Reschedule, error) {
+	if _, err := s.store.Appointments().Get(id); err != nil {
+		return nil, notFound(err, "Appointment not found")
+	}
+	return s.store.Appointments().Reschedules(id)
+}
@@ -0,0 +1,353 @@
+package appointment
+
+import (
+	"fmt"
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"sort"
+	"time"
+)
+
+// Frecuencias de repetición de una serie.
+const (
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// MaxOccurrences limita la cantidad de turnos que puede generar una serie.
+const MaxOccurrences = 104
+
+// Alcance de una modificación sobre una serie.
+const (
+	ScopeThis      = "this"      // Solo el turno indicado
+	ScopeFollowing = "following" // El turno indicado y los posteriores
+	ScopeAll       = "all"       // Todos los turnos de la serie
+)
+
+// occurrence es una fecha generada por una serie. valid es false cuando la fecha no existe
+// en el calendario, por ejemplo el día 31 en un mes de 30 días.
+type occurrence struct {
+	date  string
+	valid bool
+}
+
+// validateSeries controla la serie, incluidas las reglas entre campos que no expresan las
+// etiquetas validate, y completa los valores por defecto. Informa todos los campos inválidos
+// juntos.
+func validateSeries(series *models.AppointmentSeries) error {
+	fields := validation.Validate(*series)
+	switch {
+	case series.Count != 0 && series.Until != "":
+		fields = append(fields, models.FieldError{Field: "count", Message: "must not be set together with until"})
+	case series.Count == 0 && series.Until == "":
+		fields = append(fields, models.FieldError{Field: "count", Message: "is required when until is not set"})
+	}
+	start, startErr := time.Parse(timeslot.DateLayout, series.StartDate)
+	until, untilErr := time.Parse(timeslot.DateLayout, series.Until)
+	if startErr == nil && untilErr == nil && until.Before(start) {
+		fields = append(fields, models.FieldError{Field: "until", Message: "must not be before start_date"})
+	}
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+	if series.Duration == 0 {
+		series.Duration = DefaultDuration
+	}
+	return nil
+}
+
+// expand genera las fechas de la serie. Las repeticiones mensuales mantienen el día del mes
+// de la primera ocurrencia y las fechas inexistentes se informan como no válidas.
+func expand(series models.AppointmentSeries) ([]occurrence, error) {
+	start, err := time.ParseInLocation(timeslot.DateLayout, series.StartDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []occurrence
+	for i := 0; ; i++ {
+		if series.Count > 0 && i >= series.Count {
+			return occurrences, nil
+		}
+
+		var date time.Time
+		item := occurrence{valid: true}
+		if series.Frequency == FrequencyWeekly {
+			date = start.AddDate(0, 0, 7*series.Interval*i)
+			item.date = date.Format(timeslot.DateLayout)
+		} else {
+			first := time.Date(start.Year(), start.Month()+time.Month(series.Interval*i), 1, 0, 0, 0, 0, time.Local)
+			date = first.AddDate(0, 0, start.Day()-1)
+			item.date = date.Format(timeslot.DateLayout)
+			if date.Month() != first.Month() {
+				item.valid = false
+				item.date = fmt.Sprintf("%04d-%02d-%02d", first.Year(), int(first.Month()), start.Day())
+				date = first
+			}
+		}
+
+		if series.Until != "" && date.Format(timeslot.DateLayout) > series.Until {
+			return occurrences, nil
+		}
+		if i >= MaxOccurrences {
+			return nil, domain.FieldError("until", fmt.Sprintf("must not generate more than %d appointments", MaxOccurrences))
+		}
+		occurrences = append(occurrences, item)
+	}
+}
+
+// CreateSeries guarda la serie y genera un turno por cada ocurrencia. Las ocurrencias que se
+// superponen con otros turnos o caen fuera del horario del dentista se omiten y se informan.
+func (s *Service) CreateSeries(series models.AppointmentSeries, actor string) (models.SeriesResult, error) {
+	series.Appointments = nil
+	if err := validateSeries(&series); err != nil {
+		return models.SeriesResult{}, err
+	}
+	occurrences, err := expand(series)
+	if err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.SeriesResult{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkReferences(tx, models.Appointment{PatientID: series.PatientID, DentistID: series.DentistID}); err != nil {
+		return models.SeriesResult{}, err
+	}
+	if err := tx.Series().Create(&series); err != nil {
+		return models.SeriesResult{}, err
+	}
+	if err := audit.Record(tx, actor, audit.ResourceSeries, series.ID, audit.ActionCreate, nil, series); err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	result := models.SeriesResult{Series: &series, Appointments: []models.Appointment{}, Skipped: []models.SkippedOccurrence{}}
+	for _, item := range occurrences {
+		if !item.valid {
+			result.Skipped = append(result.Skipped, models.SkippedOccurrence{Date: item.date, Reason: "Date does not exist"})
+			continue
+		}
+
+		appointment := models.Appointment{
+			Date:        item.date,
+			Time:        series.Time,
+			Duration:    series.Duration,
+			Description: series.Description,
+			PatientID:   series.PatientID,
+			DentistID:   series.DentistID,
+			SeriesID:    series.ID,
+		}
+
+		skipped, err := s.checkOccurrence(tx, appointment)
+		if err != nil {
+			return models.SeriesResult{}, err
+		}
+		if skipped != nil {
+			result.Skipped = append(result.Skipped, *skipped)
+			continue
+		}
+
+		if err := insertAppointment(tx, &appointment); err != nil {
+			return models.SeriesResult{}, err
+		}
+		if err := record(tx, actor, audit.ActionCreate, nil, appointment); err != nil {
+			return models.SeriesResult{}, err
+		}
+		result.Appointments = append(result.Appointments, appointment)
+	}
+
+	return result, tx.Commit()
+}
+
+// GetSeries devuelve una serie con sus turnos.
+func (s *Service) GetSeries(id int) (models.AppointmentSeries, error) {
+	series, err := s.store.Series().Get(id)
+	if err != nil {
+		return series, notFound(err, "Series not found")
+	}
+	series.Appointments, err = seriesAppointments(s.store, id)
+	return series, err
+}
+
+// UpdateSeries aplica los cambios sobre un turno de la serie, ese y los siguientes, o toda
+// la serie según scope. Los turnos que ya no están pendientes o que quedarían en conflicto se
+// omiten y se informan. Los que cambian de hora, duración o dentista guardan el horario
+// anterior en el historial de reprogramaciones, como con Reschedule.
+func (s *Service) UpdateSeries(id, appointmentID int, scope string, changes models.SeriesUpdate, actor string) (models.SeriesResult, error) {
+	if scope == "" {
+		scope = ScopeThis
+	}
+	if scope != ScopeThis && scope != ScopeFollowing && scope != ScopeAll {
+		return models.SeriesResult{}, domain.Invalid("Invalid scope, expected this, following or all")
+	}
+	if err := validation.Check(changes); err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.SeriesResult{}, err
+	}
+	defer tx.Rollback()
+
+	series, err := tx.Series().Get(id)
+	if err != nil {
+		return models.SeriesResult{}, notFound(err, "Series not found")
+	}
+
+	target, err := findAppointment(tx, appointmentID)
+	if err != nil && err != repository.ErrNotFound {
+		return models.SeriesResult{}, err
+	}
+	if err == repository.ErrNotFound || target.SeriesID != id {
+		return models.SeriesResult{}, domain.NotFound("Appointment not found in series")
+	}
+
+	if changes.DentistID != 0 {
+		if err := checkReferences(tx, models.Appointment{PatientID: series.PatientID, DentistID: changes.DentistID}); err != nil {
+			return models.SeriesResult{}, err
+		}
+	}
+
+	all, err := seriesAppointments(tx, id)
+	if err != nil {
+		return models.SeriesResult{}, err
+	}
+
+	result := models.SeriesResult{Appointments: []models.Appointment{}, Skipped: []models.SkippedOccurrence{}}
+	for _, previous := range all {
+		appointment := previous
+		switch {
+		case scope == ScopeThis && appointment.ID != target.ID:
+			continue
+		case scope == ScopeFollowing && appointment.Date < target.Date:
+			continue
+		}
+
+		if appointment.Status != StatusScheduled && appointment.Status != StatusConfirmed {
+			result.Skipped = append(result.Skipped, models.SkippedOccurrence{
+				Date:          appointment.Date,
+				AppointmentID: appointment.ID,
+				Reason:        fmt.Sprintf("Appointment is %s", appointment.Status),
+			})
+			continue
+		}
+
+		applySeriesUpdate(&appointment, changes)
+		skipped, err := s.checkOccurrence(tx, appointment)
+		if err != nil {
+			return models.SeriesResult{}, err
+		}
+		if skipped != nil {
+			result.Skipped = append(result.Skipped, *skipped)
+			continue
+		}
+
+		if err := tx.Appointments().Update(appointment); err != nil {
+			return models.SeriesResult{}, err
+		}
+		action := audit.ActionPatch
+		if moved(previous, appointment) {
+			if err := addReschedule(tx, previous, appointment, changes.Reason, actor); err != nil {
+				return models.SeriesResult{}, err
+			}
+			action = audit.ActionReschedule
+		}
+		if err := record(tx, actor, action, &previous, appointment); err != nil {
+			return models.SeriesResult{}, err
+		}
+		setEndTime(&appointment)
+		result.Appointments = append(result.Appointments, appointment)
+	}
+
+	// Solo un cambio sobre toda la serie modifica la regla para futuras consultas
+	if scope == ScopeAll {
+		previous := series
+		template := models.Appointment{Time: series.Time, Duration: series.Duration, Description: series.Description, DentistID: series.DentistID}
+		applySeriesUpdate(&template, changes)
+		series.Time, series.Duration, series.Description, series.DentistID = template.Time, template.Duration, template.Description, template.DentistID
+		if err := tx.Series().Update(series); err != nil {
+			return models.SeriesResult{}, err
+		}
+		if err := audit.Record(tx, actor, audit.ResourceSeries, id, audit.ActionPatch, previous, series); err != nil {
+			return models.SeriesResult{}, err
+		}
+	}
+
+	return result, tx.Commit()
+}
+
+// applySeriesUpdate aplica sobre el turno los campos indicados en los cambios.
+func applySeriesUpdate(appointment *models.Appointment, changes models.SeriesUpdate) {
+	if changes.Time != "" {
+		appointment.Time = changes.Time
+	}
+	if changes.Duration != 0 {
+		appointment.Duration = changes.Duration
+	}
+	if changes.DentistID != 0 {
+		appointment.DentistID = changes.DentistID
+	}
+	if changes.Description != nil {
+		appointment.Description = *changes.Description
+	}
+}
+
+// checkOccurrence controla superposiciones y horario de atención de una ocurrencia. Devuelve
+// el motivo por el que se omite, o nil si puede guardarse.
+func (s *Service) checkOccurrence(repos repository.Repositories, appointment models.Appointment) (*models.SkippedOccurrence, error) {
+	conflictID, err := findConflict(repos, appointment, appointment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if conflictID != 0 {
+		return &models.SkippedOccurrence{
+			Date:                     appointment.Date,
+			AppointmentID:            appointment.ID,
+			Reason:                   fmt.Sprintf("Overlaps with appointment %d", conflictID),
+			ConflictingAppointmentID: conflictID,
+		}, nil
+	}
+
+	ok, err := s.withinWorkingHours(repos, appointment)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &models.SkippedOccurrence{
+			Date:          appointment.Date,
+			AppointmentID: appointment.ID,
+			Reason:        "Outside the dentist's working hours",
+		}, nil
+	}
+	return nil, nil
+}
+
+// seriesAppointments devuelve los turnos de una serie ordenados por fecha y hora.
+func seriesAppointments(repos repository.Repositories, seriesID int) ([]models.Appointment, error) {
+	appointments, err := repos.Appointments().List(repository.AppointmentFilter{SeriesID: seriesID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(appointments, func(i, j int) bool {
+		if appointments[i].Date != appointments[j].Date {
+			return appointments[i].Date < appointments[j].Date
+		}
+		return appointments[i].Time < appointments[j].Time
+	})
+	for i := range appointments {
+		setEndTime(&appointments[i])
+	}
+	return appointments, nil
+}
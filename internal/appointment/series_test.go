@@ -0,0 +1,81 @@
+package appointment
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name   string
+		series models.AppointmentSeries
+		// want son las fechas generadas; las que no existen en el calendario llevan un "!".
+		want []string
+	}{
+		{"weekly with count", models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 1, Count: 3},
+			[]string{"2030-01-07", "2030-01-14", "2030-01-21"}},
+		{"biweekly with count", models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 2, Count: 3},
+			[]string{"2030-01-07", "2030-01-21", "2030-02-04"}},
+		{"weekly until an occurrence", models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 1, Until: "2030-01-21"},
+			[]string{"2030-01-07", "2030-01-14", "2030-01-21"}},
+		{"biweekly until between occurrences", models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 2, Until: "2030-02-03"},
+			[]string{"2030-01-07", "2030-01-21"}},
+		{"until the start date", models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 1, Until: monday},
+			[]string{"2030-01-07"}},
+		{"monthly with count", models.AppointmentSeries{StartDate: "2030-01-15", Frequency: FrequencyMonthly, Interval: 1, Count: 3},
+			[]string{"2030-01-15", "2030-02-15", "2030-03-15"}},
+		{"monthly on the 31st", models.AppointmentSeries{StartDate: "2030-01-31", Frequency: FrequencyMonthly, Interval: 1, Count: 4},
+			[]string{"2030-01-31", "2030-02-31!", "2030-03-31", "2030-04-31!"}},
+		{"every two months until", models.AppointmentSeries{StartDate: "2030-01-31", Frequency: FrequencyMonthly, Interval: 2, Until: "2030-07-31"},
+			[]string{"2030-01-31", "2030-03-31", "2030-05-31", "2030-07-31"}},
+		{"monthly across the year", models.AppointmentSeries{StartDate: "2030-11-29", Frequency: FrequencyMonthly, Interval: 1, Until: "2031-03-01"},
+			[]string{"2030-11-29", "2030-12-29", "2031-01-29", "2031-02-29!"}},
+		{"maximum count", models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 1, Count: MaxOccurrences}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			occurrences, err := expand(test.series)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.want == nil {
+				if len(occurrences) != test.series.Count {
+					t.Errorf("expand() generated %d occurrences, want %d", len(occurrences), test.series.Count)
+				}
+				return
+			}
+			var got []string
+			for _, item := range occurrences {
+				if !item.valid {
+					item.date += "!"
+				}
+				got = append(got, item.date)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expand() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExpandTooManyOccurrences(t *testing.T) {
+	series := models.AppointmentSeries{StartDate: monday, Frequency: FrequencyWeekly, Interval: 1, Until: "2032-12-31"}
+	_, err := expand(series)
+	var invalid *domain.ValidationError
+	if !errors.As(err, &invalid) || invalid.Fields[0].Field != "until" {
+		t.Fatalf("error = %v, want a validation error on until", err)
+	}
+
+	// El límite cuenta las ocurrencias, no el rango: dos años mensuales entran
+	series.Frequency = FrequencyMonthly
+	occurrences, err := expand(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 36 {
+		t.Errorf("expand() generated %d occurrences, want 36", len(occurrences))
+	}
+}
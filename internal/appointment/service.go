@@ -0,0 +1,274 @@
+package appointment
+
+import (
+	"fmt"
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"time"
+)
+
+// Service concentra las reglas de negocio de los turnos y las series: validación, controles
+// de disponibilidad, cambios de estado y reprogramaciones. Devuelve errores de dominio (ver
+// paquete domain) y no depende de HTTP. Los cambios se registran en la auditoría a nombre
+// del actor que recibe cada método.
+type Service struct {
+	store   repository.Store
+	options Options
+}
+
+// Options ajusta las reglas de reserva. El valor cero aplica las reglas más estrictas.
+type Options struct {
+	// AllowUnscheduled acepta turnos a cualquier hora con los dentistas que no tienen
+	// horarios de atención cargados. Si es false esos dentistas no aceptan turnos.
+	AllowUnscheduled bool
+}
+
+// NewService crea un servicio de turnos sobre el almacenamiento indicado.
+func NewService(store repository.Store, options Options) *Service {
+	return &Service{store: store, options: options}
+}
+
+// List devuelve una página de los turnos que cumplen el filtro y el total sin paginar.
+func (s *Service) List(filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+	if err := checkFilter(filter, page); err != nil {
+		return nil, 0, err
+	}
+	appointments, total, err := s.store.Appointments().Find(filter, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range appointments {
+		setEndTime(&appointments[i])
+	}
+	return appointments, total, nil
+}
+
+// ListForPatient es como List pero solo con los turnos del paciente indicado, que debe existir.
+func (s *Service) ListForPatient(patientID int, filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+	if _, err := s.store.Patients().Get(patientID); err != nil {
+		return nil, 0, notFound(err, "Patient not found")
+	}
+	filter.PatientID = patientID
+	return s.List(filter, page)
+}
+
+// ListForDentist es como List pero solo con los turnos del dentista indicado, que debe existir.
+func (s *Service) ListForDentist(dentistID int, filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+	if _, err := s.store.Dentists().Get(dentistID); err != nil {
+		return nil, 0, notFound(err, "Dentist not found")
+	}
+	filter.DentistID = dentistID
+	return s.List(filter, page)
+}
+
+// Get devuelve un turno por ID.
+func (s *Service) Get(id int) (models.Appointment, error) {
+	appointment, err := findAppointment(s.store, id)
+	return appointment, notFound(err, "Appointment not found")
+}
+
+// Create valida el turno, controla superposiciones y horario de atención y lo guarda en
+// estado scheduled.
+func (s *Service) Create(appointment models.Appointment, actor string) (models.Appointment, error) {
+	// Los turnos sueltos no pertenecen a ninguna serie
+	appointment.SeriesID = 0
+	appointment.DeletedAt = ""
+	normalizeDuration(&appointment)
+	if err := validation.Check(appointment); err != nil {
+		return appointment, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return appointment, err
+	}
+	defer tx.Rollback()
+
+	if err := s.checkBooking(tx, appointment, 0); err != nil {
+		return appointment, err
+	}
+	if err := insertAppointment(tx, &appointment); err != nil {
+		return appointment, err
+	}
+	if err := record(tx, actor, audit.ActionCreate, nil, appointment); err != nil {
+		return appointment, err
+	}
+	return appointment, tx.Commit()
+}
+
+// Update reemplaza los datos de un turno. El estado y la serie se conservan, y el horario y el
+// dentista deben ser los actuales (ver checkSlotUnchanged).
+func (s *Service) Update(id int, appointment models.Appointment, actor string) (models.Appointment, error) {
+	normalizeDuration(&appointment)
+	return s.update(id, func(current *models.Appointment) error {
+		*current = appointment
+		return nil
+	}, audit.ActionUpdate, actor)
+}
+
+// Patch aplica apply sobre el turno actual y guarda el resultado si es válido. No puede cambiar
+// el horario ni el dentista. Si apply falla se devuelve su error sin cambios.
+func (s *Service) Patch(id int, apply func(*models.Appointment) error, actor string) (models.Appointment, error) {
+	return s.update(id, apply, audit.ActionPatch, actor)
+}
+
+// update es la parte común de Update y Patch. Como el horario no cambia, los controles de
+// disponibilidad solo se repiten si cambia el paciente: un turno cancelado cuyo horario se
+// volvió a reservar, o uno fuera de un horario de atención que cambió después, sigue pudiendo
+// editarse.
+func (s *Service) update(id int, apply func(*models.Appointment) error, action, actor string) (models.Appointment, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	defer tx.Rollback()
+
+	current, err := findAppointment(tx, id)
+	if err != nil {
+		return current, notFound(err, "Appointment not found")
+	}
+
+	appointment := current
+	if err := apply(&appointment); err != nil {
+		return appointment, err
+	}
+	// El estado y la serie tienen sus propios endpoints
+	appointment.ID = id
+	appointment.Status = current.Status
+	appointment.SeriesID = current.SeriesID
+	appointment.DeletedAt = ""
+
+	if err := checkSlotUnchanged(current, appointment); err != nil {
+		return appointment, err
+	}
+	// El turno no se mueve, así que puede editarse aunque ya haya ocurrido
+	if err := validation.Check(appointment, validation.RuleNotPast); err != nil {
+		return appointment, err
+	}
+	if current.PatientID != appointment.PatientID {
+		if err := s.checkBooking(tx, appointment, id); err != nil {
+			return appointment, err
+		}
+	}
+
+	if err := tx.Appointments().Update(appointment); err != nil {
+		return appointment, err
+	}
+	if err := record(tx, actor, action, &current, appointment); err != nil {
+		return appointment, err
+	}
+	if err := tx.Commit(); err != nil {
+		return appointment, err
+	}
+	setEndTime(&appointment)
+	return appointment, nil
+}
+
+// Delete da de baja un turno. Sus historiales se conservan y puede restaurarse con Restore.
+func (s *Service) Delete(id int, actor string) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	appointment, err := tx.Appointments().Get(id)
+	if err != nil {
+		return notFound(err, "Appointment not found")
+	}
+	if err := deleteAppointment(tx, appointment, time.Now().UTC().Format(time.RFC3339), actor); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Restore vuelve a activar un turno eliminado. Su paciente y su dentista deben estar activos
+// y, si el turno ocupa horario, no debe superponerse con otro.
+func (s *Service) Restore(id int, actor string) (models.Appointment, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	defer tx.Rollback()
+
+	appointment, err := tx.Appointments().GetDeleted(id)
+	if err != nil {
+		return appointment, notFound(err, "Deleted appointment not found")
+	}
+	if err := restore(tx, appointment, actor); err != nil {
+		return appointment, err
+	}
+	if err := tx.Commit(); err != nil {
+		return appointment, err
+	}
+	appointment.DeletedAt = ""
+	setEndTime(&appointment)
+	return appointment, nil
+}
+
+// insertAppointment guarda un turno nuevo en estado scheduled y completa su ID y hora de
+// finalización. No realiza ningún control.
+func insertAppointment(repos repository.Repositories, appointment *models.Appointment) error {
+	appointment.Status = StatusScheduled
+	if err := repos.Appointments().Create(appointment); err != nil {
+		return err
+	}
+	setEndTime(appointment)
+	return nil
+}
+
+// deleteAppointment da de baja el turno con la fecha deletedAt y lo registra en la auditoría.
+func deleteAppointment(repos repository.Repositories, appointment models.Appointment, deletedAt, actor string) error {
+	if err := repos.Appointments().Delete(appointment.ID, deletedAt); err != nil {
+		return err
+	}
+	deleted := appointment
+	deleted.DeletedAt = deletedAt
+	return record(repos, actor, audit.ActionDelete, &appointment, deleted)
+}
+
+// findAppointment busca un turno por ID con su hora de finalización calculada.
+func findAppointment(repos repository.Repositories, id int) (models.Appointment, error) {
+	appointment, err := repos.Appointments().Get(id)
+	if err != nil {
+		return appointment, err
+	}
+	setEndTime(&appointment)
+	return appointment, nil
+}
+
+// notFound traduce repository.ErrNotFound a un error de dominio con el mensaje indicado.
+// Cualquier otro error se devuelve sin cambios.
+func notFound(err error, message string) error {
+	if err == repository.ErrNotFound {
+		return domain.NotFound("%s", message)
+	}
+	return err
+}
+
+// checkSlotUnchanged devuelve un *domain.ValidationError si la modificación cambia la fecha,
+// la hora, la duración o el dentista del turno. Esos cambios se hacen con Reschedule, que
+// guarda el horario anterior en el historial.
+func checkSlotUnchanged(current, updated models.Appointment) error {
+	message := fmt.Sprintf("cannot be changed here, use POST /appointments/%d/reschedule", current.ID)
+	var errs []models.FieldError
+	if updated.Date != current.Date {
+		errs = append(errs, models.FieldError{Field: "date", Message: message})
+	}
+	if updated.Time != current.Time {
+		errs = append(errs, models.FieldError{Field: "time", Message: message})
+	}
+	if updated.Duration != current.Duration {
+		errs = append(errs, models.FieldError{Field: "duration", Message: message})
+	}
+	if updated.DentistID != current.DentistID {
+		errs = append(errs, models.FieldError{Field: "dentist_id", Message: message})
+	}
+	if len(errs) > 0 {
+		return &domain.ValidationError{Fields: errs}
+	}
+	return nil
+}
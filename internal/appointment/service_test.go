@@ -0,0 +1,416 @@
+package appointment
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"strings"
+	"testing"
+)
+
+// Fechas de las pruebas: lunes y martes lejanos para que no queden en el pasado.
+const (
+	monday  = "2030-01-07"
+	tuesday = "2030-01-08"
+)
+
+// newTestService crea un servicio sobre un Store en memoria con los dentistas 1 y 2, que
+// atienden los lunes de 9 a 13 con una pausa de 11 a 11:30, el dentista 3 sin horarios y los
+// pacientes 1 y 2.
+func newTestService(t *testing.T) (*Service, *memory.Store) {
+	t.Helper()
+	return newTestServiceWith(t, Options{})
+}
+
+// newTestServiceWith es como newTestService con las opciones indicadas.
+func newTestServiceWith(t *testing.T, options Options) (*Service, *memory.Store) {
+	t.Helper()
+	store := memory.New()
+	for i, license := range []string{"MP-1", "MP-2", "MP-3"} {
+		dentist := models.Dentist{LastName: "Dentist", FirstName: license, License: license}
+		mustDo(t, store.Dentists().Create(&dentist))
+		if i < 2 {
+			mustDo(t, store.Schedules().Replace(dentist.ID, []models.ScheduleBlock{{
+				DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00",
+				Breaks: []models.ScheduleBreak{{StartTime: "11:00", EndTime: "11:30"}},
+			}}))
+		}
+	}
+	for _, dni := range []string{"30111222", "30111333"} {
+		patient := models.Patient{LastName: "Patient", FirstName: dni, Address: "Calle 1", DNI: dni, RegistrationDate: "2024-01-01"}
+		mustDo(t, store.Patients().Create(&patient))
+	}
+	return NewService(store, options), store
+}
+
+// book crea un turno de 30 minutos y falla la prueba si no puede.
+func book(t *testing.T, service *Service, date, clock string, patientID, dentistID int) models.Appointment {
+	t.Helper()
+	appointment, err := service.Create(models.Appointment{Date: date, Time: clock, Duration: 30, PatientID: patientID, DentistID: dentistID}, "test")
+	mustDo(t, err)
+	return appointment
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertKind controla que err sea de la categoría want, o nil si want es nil.
+func assertKind(t *testing.T, err, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		appointment models.Appointment
+		want        error
+		conflictID  int
+	}{
+		{"free slot", models.Appointment{Date: monday, Time: "10:00", Duration: 30, PatientID: 2, DentistID: 2}, nil, 0},
+		{"same dentist overlaps", models.Appointment{Date: monday, Time: "09:15", Duration: 30, PatientID: 2, DentistID: 1}, domain.ErrConflict, 1},
+		{"same patient overlaps", models.Appointment{Date: monday, Time: "09:20", Duration: 30, PatientID: 1, DentistID: 2}, domain.ErrConflict, 1},
+		{"back to back", models.Appointment{Date: monday, Time: "09:30", Duration: 30, PatientID: 2, DentistID: 1}, nil, 0},
+		{"cancelled does not block", models.Appointment{Date: monday, Time: "12:00", Duration: 30, PatientID: 2, DentistID: 1}, nil, 0},
+		{"after hours", models.Appointment{Date: monday, Time: "12:45", Duration: 30, PatientID: 2, DentistID: 1}, domain.ErrUnprocessable, 0},
+		{"during a break", models.Appointment{Date: monday, Time: "10:45", Duration: 30, PatientID: 2, DentistID: 1}, domain.ErrUnprocessable, 0},
+		{"day off", models.Appointment{Date: tuesday, Time: "10:00", Duration: 30, PatientID: 2, DentistID: 1}, domain.ErrUnprocessable, 0},
+		{"dentist without schedule", models.Appointment{Date: monday, Time: "10:00", Duration: 30, PatientID: 2, DentistID: 3}, domain.ErrUnprocessable, 0},
+		{"unknown patient", models.Appointment{Date: monday, Time: "10:00", Duration: 30, PatientID: 9, DentistID: 1}, domain.ErrValidation, 0},
+		{"past date", models.Appointment{Date: "2020-01-06", Time: "10:00", Duration: 30, PatientID: 2, DentistID: 1}, domain.ErrValidation, 0},
+		{"too long", models.Appointment{Date: monday, Time: "09:00", Duration: models.MaxDuration + 1, PatientID: 2, DentistID: 1}, domain.ErrValidation, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			book(t, service, monday, "09:00", 1, 1)
+			cancelled := book(t, service, monday, "12:00", 1, 1)
+			_, err := service.ChangeStatus(cancelled.ID, StatusCancelled, "test", "test")
+			mustDo(t, err)
+
+			_, err = service.Create(test.appointment, "test")
+			assertKind(t, err, test.want)
+			if test.conflictID != 0 {
+				var overlap *domain.OverlapError
+				if !errors.As(err, &overlap) || overlap.AppointmentID != test.conflictID {
+					t.Errorf("error = %v, want overlap with appointment %d", err, test.conflictID)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateWithoutRequiredSchedule(t *testing.T) {
+	service, _ := newTestServiceWith(t, Options{AllowUnscheduled: true})
+	if _, err := service.Create(models.Appointment{Date: tuesday, Time: "20:00", Duration: 30, PatientID: 1, DentistID: 3}, "test"); err != nil {
+		t.Errorf("dentist without schedule: %v", err)
+	}
+	// Con horarios cargados se siguen respetando
+	_, err := service.Create(models.Appointment{Date: tuesday, Time: "10:00", Duration: 30, PatientID: 1, DentistID: 1}, "test")
+	assertKind(t, err, domain.ErrUnprocessable)
+}
+
+func TestChangeStatus(t *testing.T) {
+	tests := []struct {
+		path   []string // estados por los que pasa antes del último
+		to     string
+		reason string
+		want   error
+	}{
+		{nil, StatusConfirmed, "", nil},
+		{nil, StatusCompleted, "", nil},
+		{nil, StatusNoShow, "", nil},
+		{nil, StatusCancelled, "patient called", nil},
+		{nil, StatusCancelled, "  ", domain.ErrValidation},
+		{nil, StatusScheduled, "", domain.ErrConflict},
+		{[]string{StatusConfirmed}, StatusCompleted, "", nil},
+		{[]string{StatusConfirmed}, StatusConfirmed, "", domain.ErrConflict},
+		{[]string{StatusCompleted}, StatusCancelled, "too late", domain.ErrConflict},
+		{[]string{StatusCancelled}, StatusConfirmed, "", domain.ErrConflict},
+		{[]string{StatusNoShow}, StatusCompleted, "", domain.ErrConflict},
+	}
+	for _, test := range tests {
+		steps := append(append([]string{StatusScheduled}, test.path...), test.to)
+		t.Run(strings.Join(steps, " to "), func(t *testing.T) {
+			service, _ := newTestService(t)
+			appointment := book(t, service, monday, "09:00", 1, 1)
+			for _, status := range test.path {
+				_, err := service.ChangeStatus(appointment.ID, status, "test", "test")
+				mustDo(t, err)
+			}
+
+			changed, err := service.ChangeStatus(appointment.ID, test.to, test.reason, "test")
+			assertKind(t, err, test.want)
+
+			history, err := service.StatusHistory(appointment.ID)
+			mustDo(t, err)
+			want := len(test.path)
+			if test.want == nil {
+				want++
+				if changed.Status != test.to {
+					t.Errorf("status = %s, want %s", changed.Status, test.to)
+				}
+			}
+			if len(history) != want {
+				t.Errorf("status history has %d entries, want %d", len(history), want)
+			}
+		})
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		request models.RescheduleRequest
+		want    error
+	}{
+		{"same dentist", "", models.RescheduleRequest{Date: monday, Time: "12:00"}, nil},
+		{"other dentist and duration", StatusConfirmed, models.RescheduleRequest{Date: monday, Time: "09:30", Duration: 60, DentistID: 2}, nil},
+		{"overlap", "", models.RescheduleRequest{Date: monday, Time: "10:15"}, domain.ErrConflict},
+		{"outside working hours", "", models.RescheduleRequest{Date: tuesday, Time: "10:00"}, domain.ErrUnprocessable},
+		{"completed", StatusCompleted, models.RescheduleRequest{Date: monday, Time: "12:00"}, domain.ErrConflict},
+		{"missing time", "", models.RescheduleRequest{Date: monday}, domain.ErrValidation},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			appointment := book(t, service, monday, "09:00", 1, 1)
+			book(t, service, monday, "10:00", 2, 1)
+			if test.status != "" {
+				_, err := service.ChangeStatus(appointment.ID, test.status, "test", "test")
+				mustDo(t, err)
+			}
+
+			_, err := service.Reschedule(appointment.ID, test.request, "test")
+			assertKind(t, err, test.want)
+
+			history, err := service.RescheduleHistory(appointment.ID)
+			mustDo(t, err)
+			if test.want != nil {
+				if len(history) != 0 {
+					t.Errorf("failed reschedule left %d history entries", len(history))
+				}
+				return
+			}
+			if len(history) != 1 {
+				t.Fatalf("reschedule history has %d entries, want 1", len(history))
+			}
+			entry := history[0]
+			if entry.PreviousDate != monday || entry.PreviousTime != "09:00" || entry.PreviousDentistID != 1 ||
+				entry.NewTime != test.request.Time || entry.ChangedBy != "test" {
+				t.Errorf("unexpected history entry %+v", entry)
+			}
+		})
+	}
+}
+
+func TestUpdateRejectsSlotChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*models.Appointment)
+		fields []string
+	}{
+		{"description", func(a *models.Appointment) { a.Description = "control" }, nil},
+		{"patient", func(a *models.Appointment) { a.PatientID = 2 }, nil},
+		{"time", func(a *models.Appointment) { a.Time = "10:00" }, []string{"time"}},
+		{"date and dentist", func(a *models.Appointment) { a.Date = tuesday; a.DentistID = 2 }, []string{"date", "dentist_id"}},
+		{"duration", func(a *models.Appointment) { a.Duration = 45 }, []string{"duration"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			appointment := book(t, service, monday, "09:00", 1, 1)
+
+			_, err := service.Patch(appointment.ID, func(a *models.Appointment) error {
+				test.change(a)
+				return nil
+			}, "test")
+			if test.fields == nil {
+				mustDo(t, err)
+				return
+			}
+
+			var invalid *domain.ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+			var got []string
+			for _, field := range invalid.Fields {
+				got = append(got, field.Field)
+			}
+			if len(got) != len(test.fields) {
+				t.Fatalf("invalid fields = %v, want %v", got, test.fields)
+			}
+			for i := range got {
+				if got[i] != test.fields[i] {
+					t.Errorf("invalid fields = %v, want %v", got, test.fields)
+				}
+			}
+			if history, _ := service.RescheduleHistory(appointment.ID); len(history) != 0 {
+				t.Errorf("rejected patch left %d reschedule entries", len(history))
+			}
+		})
+	}
+}
+
+func TestUpdateCancelledAppointmentWithRebookedSlot(t *testing.T) {
+	service, _ := newTestService(t)
+	cancelled := book(t, service, monday, "09:00", 1, 1)
+	_, err := service.ChangeStatus(cancelled.ID, StatusCancelled, "patient called", "test")
+	mustDo(t, err)
+	book(t, service, monday, "09:00", 2, 1)
+
+	cancelled.Description = "called to cancel"
+	updated, err := service.Update(cancelled.ID, cancelled, "test")
+	mustDo(t, err)
+	if updated.Description != "called to cancel" || updated.Status != StatusCancelled {
+		t.Errorf("updated = %+v, want the new description on the cancelled appointment", updated)
+	}
+}
+
+func TestCreateSeriesValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		series models.AppointmentSeries
+		fields []string
+	}{
+		{"count and until", models.AppointmentSeries{Frequency: "weekly", Count: 2, Until: "2030-02-01"}, []string{"count"}},
+		{"neither count nor until", models.AppointmentSeries{Frequency: "weekly"}, []string{"count"}},
+		{"until before start_date", models.AppointmentSeries{Frequency: "weekly", Until: "2029-12-31"}, []string{"until"}},
+		{"every invalid field", models.AppointmentSeries{Frequency: "daily", Count: 200, Until: "2029-12-31"}, []string{"frequency", "count", "count", "until"}},
+		{"too many occurrences", models.AppointmentSeries{Frequency: "weekly", Until: "2040-01-01"}, []string{"until"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, _ := newTestService(t)
+			series := test.series
+			series.PatientID, series.DentistID, series.StartDate, series.Time = 1, 1, monday, "09:00"
+
+			_, err := service.CreateSeries(series, "test")
+			var validationErr *domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+			var fields []string
+			for _, field := range validationErr.Fields {
+				fields = append(fields, field.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(test.fields, ",") {
+				t.Errorf("invalid fields = %v, want %v", fields, test.fields)
+			}
+		})
+	}
+}
+
+func TestUpdateSeries(t *testing.T) {
+	tests := []struct {
+		scope   string
+		target  int // posición del turno indicado en la serie
+		changed []int
+		want    error
+	}{
+		{ScopeThis, 1, []int{1}, nil},
+		{ScopeFollowing, 1, []int{1, 2, 3}, nil},
+		{ScopeAll, 2, []int{0, 1, 2, 3}, nil},
+		{"", 2, []int{2}, nil},
+		{"some", 0, nil, domain.ErrInvalid},
+	}
+	for _, test := range tests {
+		t.Run(test.scope, func(t *testing.T) {
+			service, _ := newTestService(t)
+			created, err := service.CreateSeries(models.AppointmentSeries{PatientID: 1, DentistID: 1, StartDate: monday,
+				Time: "09:00", Duration: 30, Frequency: "weekly", Count: 4}, "test")
+			mustDo(t, err)
+			if len(created.Appointments) != 4 {
+				t.Fatalf("series created %d appointments, want 4", len(created.Appointments))
+			}
+			seriesID := created.Series.ID
+			target := created.Appointments[test.target]
+
+			result, err := service.UpdateSeries(seriesID, target.ID, test.scope, models.SeriesUpdate{Time: "10:00", Reason: "holiday"}, "test")
+			assertKind(t, err, test.want)
+			if err != nil {
+				return
+			}
+
+			if len(result.Appointments) != len(test.changed) {
+				t.Errorf("updated %d appointments, want %d", len(result.Appointments), len(test.changed))
+			}
+			changed := map[int]bool{}
+			for _, i := range test.changed {
+				changed[created.Appointments[i].ID] = true
+			}
+			for _, before := range created.Appointments {
+				after, err := service.Get(before.ID)
+				mustDo(t, err)
+				want := "09:00"
+				if changed[before.ID] {
+					want = "10:00"
+				}
+				if after.Time != want {
+					t.Errorf("appointment on %s starts at %s, want %s", after.Date, after.Time, want)
+				}
+				history, err := service.RescheduleHistory(before.ID)
+				mustDo(t, err)
+				if changed[before.ID] != (len(history) == 1) {
+					t.Errorf("appointment on %s has %d reschedules, moved = %v", after.Date, len(history), changed[before.ID])
+				} else if len(history) == 1 && (history[0].PreviousTime != "09:00" || history[0].Reason != "holiday") {
+					t.Errorf("reschedule = %+v, want a move from 09:00 because of a holiday", history[0])
+				}
+			}
+
+			series, err := service.GetSeries(seriesID)
+			mustDo(t, err)
+			wantRule := "09:00"
+			if test.scope == ScopeAll {
+				wantRule = "10:00"
+			}
+			if series.Time != wantRule {
+				t.Errorf("series time = %s, want %s", series.Time, wantRule)
+			}
+		})
+	}
+}
+
+func TestUpdateSeriesSkipsClosedAndBlockedOccurrences(t *testing.T) {
+	service, _ := newTestService(t)
+	created, err := service.CreateSeries(models.AppointmentSeries{PatientID: 1, DentistID: 1, StartDate: monday,
+		Time: "09:00", Duration: 30, Frequency: "weekly", Count: 3}, "test")
+	mustDo(t, err)
+	first, second, third := created.Appointments[0], created.Appointments[1], created.Appointments[2]
+
+	_, err = service.ChangeStatus(first.ID, StatusCompleted, "", "test")
+	mustDo(t, err)
+	blocker := book(t, service, second.Date, "10:00", 2, 1)
+
+	result, err := service.UpdateSeries(created.Series.ID, first.ID, ScopeAll, models.SeriesUpdate{Time: "10:00"}, "test")
+	mustDo(t, err)
+
+	if len(result.Appointments) != 1 || result.Appointments[0].ID != third.ID {
+		t.Errorf("updated %+v, want only appointment %d", result.Appointments, third.ID)
+	}
+	skipped := map[int]models.SkippedOccurrence{}
+	for _, item := range result.Skipped {
+		skipped[item.AppointmentID] = item
+	}
+	if _, ok := skipped[first.ID]; !ok {
+		t.Errorf("completed appointment %d was not skipped", first.ID)
+	}
+	if skipped[second.ID].ConflictingAppointmentID != blocker.ID {
+		t.Errorf("appointment %d skipped as %+v, want a conflict with %d", second.ID, skipped[second.ID], blocker.ID)
+	}
+}
@@ -0,0 +1,93 @@
+package appointment
+
+import (
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"strings"
+	"time"
+)
+
+// Estados posibles de un turno.
+const (
+	StatusScheduled = "scheduled"
+	StatusConfirmed = "confirmed"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusNoShow    = "no_show"
+)
+
+// transitions define a qué estados se puede pasar desde cada estado. Los estados que no
+// figuran como clave son finales.
+var transitions = map[string][]string{
+	StatusScheduled: {StatusConfirmed, StatusCompleted, StatusCancelled, StatusNoShow},
+	StatusConfirmed: {StatusCompleted, StatusCancelled, StatusNoShow},
+}
+
+// canTransition indica si un turno puede pasar del estado from al estado to.
+func canTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus lleva un turno al estado to, registrando quién hizo el cambio y cuándo. Para
+// cancelar un turno el motivo es obligatorio.
+func (s *Service) ChangeStatus(id int, to, reason, actor string) (models.Appointment, error) {
+	reason = strings.TrimSpace(reason)
+	if to == StatusCancelled && reason == "" {
+		return models.Appointment{}, domain.FieldError("reason", "is required")
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	defer tx.Rollback()
+
+	appointment, err := findAppointment(tx, id)
+	if err != nil {
+		return appointment, notFound(err, "Appointment not found")
+	}
+	if !canTransition(appointment.Status, to) {
+		return appointment, domain.Conflict("Cannot change appointment from %s to %s", appointment.Status, to)
+	}
+
+	previous := appointment
+	if err := recordStatusChange(tx, &appointment, to, reason, actor); err != nil {
+		return appointment, err
+	}
+	if err := record(tx, actor, audit.ActionStatus, &previous, appointment); err != nil {
+		return appointment, err
+	}
+	return appointment, tx.Commit()
+}
+
+// StatusHistory devuelve los cambios de estado de un turno.
+func (s *Service) StatusHistory(id int) ([]models.StatusChange, error) {
+	if _, err := s.store.Appointments().Get(id); err != nil {
+		return nil, notFound(err, "Appointment not found")
+	}
+	return s.store.Appointments().StatusChanges(id)
+}
+
+// recordStatusChange actualiza el estado del turno y guarda el cambio en el historial.
+func recordStatusChange(repos repository.Repositories, appointment *models.Appointment, to, reason, actor string) error {
+	change := models.StatusChange{
+		AppointmentID: appointment.ID,
+		FromStatus:    appointment.Status,
+		ToStatus:      to,
+		Reason:        reason,
+		ChangedBy:     actor,
+		ChangedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	appointment.Status = to
+	if err := repos.Appointments().Update(*appointment); err != nil {
+		return err
+	}
+	return repos.Appointments().AddStatusChange(&change)
+}
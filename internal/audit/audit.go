@@ -0,0 +1,125 @@
+// Package audit registra quién creó, modificó o eliminó cada dentista, paciente, turno y
+// serie, con los campos que cambiaron. Los servicios llaman a Record dentro de la misma
+// transacción que el cambio, así que no hay cambios sin registro ni registros sin cambio.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"time"
+)
+
+// Recursos auditados.
+const (
+	ResourceDentist     = "dentist"
+	ResourcePatient     = "patient"
+	ResourceAppointment = "appointment"
+	ResourceSeries      = "series"
+)
+
+// Acciones registradas.
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionPatch      = "patch"
+	ActionDelete     = "delete"
+	ActionRestore    = "restore"
+	ActionStatus     = "status"
+	ActionReschedule = "reschedule"
+)
+
+// Resources son los recursos por los que se puede filtrar el registro.
+var Resources = []string{ResourceDentist, ResourcePatient, ResourceAppointment, ResourceSeries}
+
+// computed son los campos calculados de cada recurso. No se guardan, así que no se comparan.
+var computed = map[string][]string{
+	ResourceAppointment: {"end_time"},
+}
+
+// Record guarda un registro de auditoría con los campos que difieren entre before y after.
+// before es nil en un alta.
+func Record(repos repository.Repositories, actor, resource string, id int, action string, before, after interface{}) error {
+	changes, err := Diff(before, after, computed[resource]...)
+	if err != nil {
+		return err
+	}
+	entry := models.AuditEntry{
+		Actor:      actor,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Resource:   resource,
+		ResourceID: id,
+		Action:     action,
+		Changes:    changes,
+	}
+	return repos.Audit().Add(&entry)
+}
+
+// Diff compara las representaciones JSON de before y after y devuelve los campos que
+// cambiaron, salvo los de ignore. Un campo ausente en uno de los dos, como en un alta, vale
+// null.
+func Diff(before, after interface{}, ignore ...string) (map[string]models.AuditChange, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range ignore {
+		delete(old, name)
+		delete(updated, name)
+	}
+
+	changes := map[string]models.AuditChange{}
+	for name := range old {
+		if _, ok := updated[name]; !ok {
+			updated[name] = nil
+		}
+	}
+	for name, value := range updated {
+		if bytes.Equal(old[name], value) {
+			continue
+		}
+		var change models.AuditChange
+		if err := decode(old[name], &change.Before); err != nil {
+			return nil, err
+		}
+		if err := decode(value, &change.After); err != nil {
+			return nil, err
+		}
+		changes[name] = change
+	}
+	return changes, nil
+}
+
+// fields devuelve los campos JSON de v sin los que valen null. Con nil devuelve un mapa vacío.
+func fields(v interface{}) (map[string]json.RawMessage, error) {
+	result := map[string]json.RawMessage{}
+	if v == nil {
+		return result, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	for name, value := range result {
+		if string(value) == "null" {
+			delete(result, name)
+		}
+	}
+	return result, nil
+}
+
+func decode(raw json.RawMessage, v *interface{}) error {
+	if raw == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	patient := models.Patient{ID: 1, LastName: "Lopez", FirstName: "Ana", DNI: "30111222", RegistrationDate: "2030-01-07"}
+	moved := patient
+	moved.Address = "Calle 1"
+	moved.LastName = "López"
+	deleted := patient
+	deleted.DeletedAt = "2030-01-08T10:00:00Z"
+
+	appointment := models.Appointment{ID: 1, Date: "2030-01-07", Time: "10:00", Duration: 30, EndTime: "10:30", Status: "scheduled", PatientID: 1, DentistID: 2}
+	longer := appointment
+	longer.Duration = 60
+	longer.EndTime = "11:00"
+
+	tests := []struct {
+		name          string
+		before, after interface{}
+		ignore        []string
+		want          map[string]models.AuditChange
+	}{
+		{"no changes", patient, patient, nil, map[string]models.AuditChange{}},
+		{"changed fields", patient, moved, nil, map[string]models.AuditChange{
+			"address":   {Before: "", After: "Calle 1"},
+			"last_name": {Before: "Lopez", After: "López"},
+		}},
+		{"omitted field set", patient, deleted, nil, map[string]models.AuditChange{
+			"deleted_at": {Before: nil, After: "2030-01-08T10:00:00Z"},
+		}},
+		{"omitted field cleared", deleted, patient, nil, map[string]models.AuditChange{
+			"deleted_at": {Before: "2030-01-08T10:00:00Z", After: nil},
+		}},
+		{"ignored fields", appointment, longer, []string{"end_time"}, map[string]models.AuditChange{
+			"duration": {Before: float64(30), After: float64(60)},
+		}},
+		{"creation", nil, models.Dentist{ID: 3, LastName: "Perez", FirstName: "Juan", License: "MP-1"}, nil, map[string]models.AuditChange{
+			"id":         {Before: nil, After: float64(3)},
+			"last_name":  {Before: nil, After: "Perez"},
+			"first_name": {Before: nil, After: "Juan"},
+			"license":    {Before: nil, After: "MP-1"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Diff(test.before, test.after, test.ignore...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Diff() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	store := memory.New()
+	appointment := models.Appointment{ID: 1, Date: "2030-01-07", Time: "10:00", Duration: 30, EndTime: "10:30", Status: "scheduled", PatientID: 1, DentistID: 2}
+	later := appointment
+	later.Time, later.EndTime = "11:00", "11:30"
+
+	tx, err := store.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Record(tx, "ana", ResourceAppointment, 1, ActionCreate, nil, appointment); err != nil {
+		t.Fatal(err)
+	}
+	if err := Record(tx, "reception", ResourceAppointment, 1, ActionReschedule, appointment, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, total, err := NewService(store).List(repository.AuditFilter{Actor: "reception"}, repository.Page{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(entries) != 1 {
+		t.Fatalf("entries = %+v, total %d, want only the reschedule", entries, total)
+	}
+	want := map[string]models.AuditChange{"time": {Before: "10:00", After: "11:00"}}
+	if entry := entries[0]; entry.Action != ActionReschedule || entry.Timestamp == "" || !reflect.DeepEqual(entry.Changes, want) {
+		t.Errorf("entry = %+v, want the time change without end_time", entry)
+	}
+}
+
+func TestList(t *testing.T) {
+	service := NewService(memory.New())
+	tests := []struct {
+		name   string
+		filter repository.AuditFilter
+		page   repository.Page
+		fields []string
+	}{
+		{"dates", repository.AuditFilter{From: "2030-01-07", To: "2030-01-07"}, repository.Page{Limit: 10}, nil},
+		{"timestamps", repository.AuditFilter{From: "2030-01-07T10:00:00-03:00"}, repository.Page{Limit: 10}, nil},
+		{"unknown resource", repository.AuditFilter{Resource: "user"}, repository.Page{Limit: 10}, []string{"resource"}},
+		{"invalid dates", repository.AuditFilter{From: "07/01/2030", To: "tomorrow"}, repository.Page{Limit: 10}, []string{"from", "to"}},
+		{"to before from", repository.AuditFilter{From: "2030-01-08", To: "2030-01-07"}, repository.Page{Limit: 10}, []string{"to"}},
+		{"invalid page", repository.AuditFilter{}, repository.Page{Limit: 0, Sort: []repository.SortField{{Field: "changes"}}}, []string{"limit", "sort"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := service.List(test.filter, test.page)
+			if test.fields == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var invalid *domain.ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+			var fields []string
+			for _, field := range invalid.Fields {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(fields, test.fields) {
+				t.Errorf("fields = %v, want %v", fields, test.fields)
+			}
+		})
+	}
+}
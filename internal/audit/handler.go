@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"net/http"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/web"
+)
+
+// GET: Consultar auditoría
+// @Summary Consultar el registro de auditoría
+// @Description Devuelve una página de los cambios hechos sobre dentistas, pacientes, turnos y series,
+// @Description con quién los hizo y los campos que cambiaron. El total sin paginar va en X-Total-Count
+// @Description y los enlaces a otras páginas en Link.
+// @Tags Auditoría
+// @Produce json
+// @Param resource query string false "Recurso: dentist, patient, appointment o series"
+// @Param id query int false "ID del recurso"
+// @Param actor query string false "Usuario o clave de API que hizo el cambio"
+// @Param from query string false "Desde esta fecha (YYYY-MM-DD) o momento (RFC 3339), inclusive"
+// @Param to query string false "Hasta esta fecha (YYYY-MM-DD) o momento (RFC 3339), inclusive"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Param sort query string false "Campos de orden separados por comas, con - para orden descendente: id, timestamp, actor, resource, resource_id, action"
+// @Success 200 {array} models.AuditEntry
+// @Header 200 {integer} X-Total-Count "Total de registros sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Router /audit [get]
+func GetAuditLog(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := web.PageQuery(r)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		id, err := web.QueryInt(r, "id")
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		query := r.URL.Query()
+		filter := repository.AuditFilter{
+			Resource:   query.Get("resource"),
+			ResourceID: id,
+			Actor:      query.Get("actor"),
+			From:       query.Get("from"),
+			To:         query.Get("to"),
+		}
+
+		entries, total, err := service.List(filter, page)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		web.List(w, r, page, total, entries)
+	}
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"strings"
+	"time"
+)
+
+// Service consulta el registro de auditoría.
+type Service struct {
+	store repository.Store
+}
+
+// NewService crea un servicio de auditoría sobre el almacenamiento indicado.
+func NewService(store repository.Store) *Service {
+	return &Service{store: store}
+}
+
+// List devuelve una página de los registros que cumplen el filtro y el total sin paginar.
+// From y To pueden ser fechas YYYY-MM-DD, que abarcan el día completo en UTC, o momentos
+// RFC 3339.
+func (s *Service) List(filter repository.AuditFilter, page repository.Page) ([]models.AuditEntry, int, error) {
+	var errs []models.FieldError
+	if err := page.Check(repository.AuditSortFields); err != nil {
+		var invalid *domain.ValidationError
+		if !errors.As(err, &invalid) {
+			return nil, 0, err
+		}
+		errs = append(errs, invalid.Fields...)
+	}
+	if filter.Resource != "" && !isResource(filter.Resource) {
+		errs = append(errs, models.FieldError{Field: "resource", Message: "must be one of " + strings.Join(Resources, ", ")})
+	}
+	from, ok := bound(filter.From, false)
+	if !ok {
+		errs = append(errs, models.FieldError{Field: "from", Message: "must be a date in YYYY-MM-DD format or an RFC 3339 timestamp"})
+	}
+	to, ok := bound(filter.To, true)
+	if !ok {
+		errs = append(errs, models.FieldError{Field: "to", Message: "must be a date in YYYY-MM-DD format or an RFC 3339 timestamp"})
+	}
+	if from != "" && to != "" && to < from {
+		errs = append(errs, models.FieldError{Field: "to", Message: "must not be before from"})
+	}
+	if len(errs) > 0 {
+		return nil, 0, &domain.ValidationError{Fields: errs}
+	}
+
+	filter.From, filter.To = from, to
+	return s.store.Audit().Find(filter, page)
+}
+
+// bound convierte un límite del filtro al formato guardado, RFC 3339 en UTC. Una fecha se
+// toma desde el comienzo del día o, si end es true, hasta su último segundo.
+func bound(value string, end bool) (string, bool) {
+	if value == "" {
+		return "", true
+	}
+	if date, err := time.Parse(timeslot.DateLayout, value); err == nil {
+		if end {
+			date = date.Add(24*time.Hour - time.Second)
+		}
+		return date.Format(time.RFC3339), true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", false
+	}
+	return t.UTC().Format(time.RFC3339), true
+}
+
+func isResource(value string) bool {
+	for _, resource := range Resources {
+		if resource == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+// Package auth autentica a los usuarios de la API: verifica credenciales, emite y valida los
+// JWT y guarda el usuario autenticado en el contexto del pedido.
+package auth
+
+import (
+	"context"
+	"odontology-appointments/pkg/models"
+)
+
+type contextKey struct{}
+
+// WithUser devuelve una copia de ctx con el usuario autenticado.
+func WithUser(ctx context.Context, user models.User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFrom devuelve el usuario autenticado del contexto. ok es false si el pedido no trae
+// un token válido.
+func UserFrom(ctx context.Context) (user models.User, ok bool) {
+	user, ok = ctx.Value(contextKey{}).(models.User)
+	return user, ok
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"odontology-appointments/pkg/models"
+	"time"
+)
+
+// Credentials verifica los inicios de sesión y las renovaciones contra las cuentas de usuario.
+type Credentials interface {
+	// Authenticate devuelve el usuario si la contraseña es correcta, o un error
+	// domain.ErrUnauthorized si no.
+	Authenticate(username, password string) (models.User, error)
+	// Current devuelve los datos vigentes del usuario de un token de renovación emitido en
+	// issuedAt. Falla con domain.ErrUnauthorized si la cuenta ya no existe, está bloqueada o
+	// cambió de contraseña después de emitirse el token.
+	Current(username string, issuedAt time.Time) (models.User, error)
+}
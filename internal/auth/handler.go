@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/web"
+	"odontology-appointments/pkg/models"
+)
+
+// POST: Iniciar sesión
+// @Summary Iniciar sesión
+// @Description Devuelve un token de acceso, a enviar como "Authorization: Bearer <token>",
+// @Description y un token de renovación para pedir uno nuevo cuando venza.
+// @Tags Autenticación
+// @Accept json
+// @Produce json
+// @Param credenciales body models.LoginRequest true "Usuario y contraseña"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Router /auth/login [post]
+func Login(credentials Credentials, tokens *Tokens) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request models.LoginRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		user, err := credentials.Authenticate(request.Username, request.Password)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		issue(w, tokens, user)
+	}
+}
+
+// POST: Renovar tokens
+// @Summary Renovar los tokens
+// @Description Cambia un token de renovación vigente por un nuevo par de tokens con los datos
+// @Description actuales de la cuenta. Los tokens emitidos antes de un cambio de contraseña no se renuevan.
+// @Tags Autenticación
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshRequest true "Token de renovación"
+// @Success 200 {object} models.TokenPair
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Router /auth/refresh [post]
+func Refresh(credentials Credentials, tokens *Tokens) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request models.RefreshRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		user, issuedAt, err := tokens.ParseRefresh(request.RefreshToken)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		user, err = credentials.Current(user.Username, issuedAt)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		issue(w, tokens, user)
+	}
+}
+
+// GET: Usuario autenticado
+// @Summary Obtener el usuario autenticado
+// @Tags Autenticación
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {object} models.User
+// @Failure 401 {object} models.Error
+// @Router /auth/me [get]
+func Me() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := UserFrom(r.Context())
+		if !ok {
+			web.Fail(w, domain.Unauthorized("Authentication required"))
+			return
+		}
+		web.JSON(w, http.StatusOK, user)
+	}
+}
+
+// issue responde un nuevo par de tokens para el usuario.
+func issue(w http.ResponseWriter, tokens *Tokens, user models.User) {
+	pair, err := tokens.Issue(user)
+	if err != nil {
+		web.InternalError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	web.JSON(w, http.StatusOK, pair)
+}
@@ -0,0 +1,95 @@
+package auth
+
+import "odontology-appointments/pkg/models"
+
+// Permission es una acción sobre un recurso, con la forma "recurso:acción". read permite
+// consultar, write crear y modificar, y admin eliminar, restaurar y ver los eliminados.
+type Permission string
+
+const (
+	DentistsRead      Permission = "dentists:read"
+	DentistsWrite     Permission = "dentists:write"
+	DentistsAdmin     Permission = "dentists:admin"
+	PatientsRead      Permission = "patients:read"
+	PatientsWrite     Permission = "patients:write"
+	PatientsAdmin     Permission = "patients:admin"
+	AppointmentsRead  Permission = "appointments:read"
+	AppointmentsWrite Permission = "appointments:write"
+	AppointmentsAdmin Permission = "appointments:admin"
+	// APIKeysAdmin permite crear, listar y revocar claves de API, UsersAdmin crear cuentas de
+	// usuario y cambiar sus contraseñas y AuditRead consultar el registro de auditoría. Son
+	// solo de los administradores y no pueden darse a una clave.
+	APIKeysAdmin Permission = "api_keys:admin"
+	UsersAdmin   Permission = "users:admin"
+	AuditRead    Permission = "audit:read"
+)
+
+// Roles de los usuarios.
+const (
+	RoleAdmin        = "admin"
+	RoleReceptionist = "receptionist"
+	RoleDentist      = "dentist"
+)
+
+// rolePermissions son los permisos de cada rol. El rol dentist además queda limitado a sus
+// propios turnos y a los pacientes con los que tiene turnos (ver DentistScope); por eso puede
+// modificar esos pacientes pero no dar de alta pacientes nuevos.
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		DentistsRead, DentistsWrite, DentistsAdmin,
+		PatientsRead, PatientsWrite, PatientsAdmin,
+		AppointmentsRead, AppointmentsWrite, AppointmentsAdmin,
+		APIKeysAdmin, UsersAdmin, AuditRead,
+	},
+	RoleReceptionist: {
+		DentistsRead, DentistsWrite,
+		PatientsRead, PatientsWrite,
+		AppointmentsRead, AppointmentsWrite,
+	},
+	RoleDentist: {
+		DentistsRead,
+		PatientsRead, PatientsWrite,
+		AppointmentsRead, AppointmentsWrite,
+	},
+}
+
+// ValidRole indica si role es uno de los roles conocidos.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// ValidScope indica si scope es un permiso que puede darse a una clave de API.
+func ValidScope(scope string) bool {
+	for _, p := range rolePermissions[RoleAdmin] {
+		if string(p) == scope {
+			return p != APIKeysAdmin && p != UsersAdmin && p != AuditRead
+		}
+	}
+	return false
+}
+
+// Can indica si el usuario tiene el permiso por su rol o, si es una clave de API, por sus
+// scopes.
+func Can(user models.User, permission Permission) bool {
+	for _, p := range rolePermissions[user.Role] {
+		if p == permission {
+			return true
+		}
+	}
+	for _, scope := range user.Scopes {
+		if scope == string(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// DentistScope devuelve el ID del dentista al que están limitados los datos que ve el
+// usuario, o 0 si puede ver los de todos.
+func DentistScope(user models.User) int {
+	if user.Role == RoleDentist {
+		return user.DentistID
+	}
+	return 0
+}
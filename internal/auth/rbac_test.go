@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"odontology-appointments/pkg/models"
+	"testing"
+)
+
+func TestCan(t *testing.T) {
+	admin := models.User{Username: "ana", Role: RoleAdmin}
+	receptionist := models.User{Username: "reception", Role: RoleReceptionist}
+	dentist := models.User{Username: "doctor", Role: RoleDentist, DentistID: 3}
+	key := models.User{Username: "api-key:1:lab", Scopes: []string{string(AppointmentsRead), string(PatientsRead)}}
+	nobody := models.User{Username: "ghost", Role: "owner"}
+
+	tests := []struct {
+		permission Permission
+		// allowed son los usuarios con el permiso, en el orden admin, receptionist, dentist,
+		// clave de API y rol desconocido.
+		allowed [5]bool
+	}{
+		{DentistsRead, [5]bool{true, true, true, false, false}},
+		{DentistsWrite, [5]bool{true, true, false, false, false}},
+		{DentistsAdmin, [5]bool{true, false, false, false, false}},
+		{PatientsRead, [5]bool{true, true, true, true, false}},
+		{PatientsWrite, [5]bool{true, true, true, false, false}},
+		{PatientsAdmin, [5]bool{true, false, false, false, false}},
+		{AppointmentsRead, [5]bool{true, true, true, true, false}},
+		{AppointmentsWrite, [5]bool{true, true, true, false, false}},
+		{AppointmentsAdmin, [5]bool{true, false, false, false, false}},
+		{APIKeysAdmin, [5]bool{true, false, false, false, false}},
+		{UsersAdmin, [5]bool{true, false, false, false, false}},
+		{AuditRead, [5]bool{true, false, false, false, false}},
+	}
+	for _, test := range tests {
+		for i, user := range []models.User{admin, receptionist, dentist, key, nobody} {
+			if got := Can(user, test.permission); got != test.allowed[i] {
+				t.Errorf("Can(%s, %s) = %v, want %v", user.Username, test.permission, got, test.allowed[i])
+			}
+		}
+	}
+}
+
+func TestValidScope(t *testing.T) {
+	tests := map[string]bool{
+		"appointments:read":   true,
+		"patients:admin":      true,
+		"dentists:write":      true,
+		"api_keys:admin":      false,
+		"users:admin":         false,
+		"audit:read":          false,
+		"appointments:write ": false,
+		"":                    false,
+	}
+	for scope, want := range tests {
+		if got := ValidScope(scope); got != want {
+			t.Errorf("ValidScope(%q) = %v, want %v", scope, got, want)
+		}
+	}
+}
+
+func TestDentistScope(t *testing.T) {
+	tests := []struct {
+		user models.User
+		want int
+	}{
+		{models.User{Role: RoleDentist, DentistID: 3}, 3},
+		{models.User{Role: RoleAdmin, DentistID: 3}, 0},
+		{models.User{Role: RoleReceptionist}, 0},
+		{models.User{Scopes: []string{"appointments:read"}}, 0},
+	}
+	for _, test := range tests {
+		if got := DentistScope(test.user); got != test.want {
+			t.Errorf("DentistScope(%+v) = %d, want %d", test.user, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritmos de firma admitidos.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+// Duración por defecto de los tokens.
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 7 * 24 * time.Hour
+)
+
+// Tipos de token, en el claim "typ". Un token de renovación no sirve para autenticar pedidos
+// ni uno de acceso para renovar.
+const (
+	accessType  = "access"
+	refreshType = "refresh"
+)
+
+const issuer = "odontology-appointments"
+
+// Config define cómo se firman los tokens. Con HS256 se usa Secret; con RS256, PrivateKey
+// para firmar y PublicKey para verificar. Si falta PublicKey se toma la de PrivateKey.
+type Config struct {
+	Algorithm  string
+	Secret     []byte
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// Tokens emite y valida los JWT de la API.
+type Tokens struct {
+	method     jwt.SigningMethod
+	signKey    interface{}
+	verifyKey  interface{}
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// claims son los datos de un token. El subject es el nombre de usuario.
+type claims struct {
+	jwt.RegisteredClaims
+	UserID    int    `json:"uid,omitempty"`
+	Role      string `json:"role"`
+	DentistID int    `json:"did,omitempty"`
+	Type      string `json:"typ"`
+}
+
+// NewTokens valida la configuración y devuelve el emisor de tokens.
+func NewTokens(config Config) (*Tokens, error) {
+	t := &Tokens{accessTTL: config.AccessTTL, refreshTTL: config.RefreshTTL}
+	if t.accessTTL == 0 {
+		t.accessTTL = DefaultAccessTTL
+	}
+	if t.refreshTTL == 0 {
+		t.refreshTTL = DefaultRefreshTTL
+	}
+
+	switch config.Algorithm {
+	case HS256, "":
+		if len(config.Secret) < 32 {
+			return nil, errors.New("the HS256 secret must have at least 32 bytes")
+		}
+		t.method, t.signKey, t.verifyKey = jwt.SigningMethodHS256, config.Secret, config.Secret
+	case RS256:
+		if config.PrivateKey == nil {
+			return nil, errors.New("RS256 requires a private key")
+		}
+		public := config.PublicKey
+		if public == nil {
+			public = &config.PrivateKey.PublicKey
+		}
+		t.method, t.signKey, t.verifyKey = jwt.SigningMethodRS256, config.PrivateKey, public
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q, use %s or %s", config.Algorithm, HS256, RS256)
+	}
+	return t, nil
+}
+
+// Issue emite un token de acceso y uno de renovación para el usuario.
+func (t *Tokens) Issue(user models.User) (models.TokenPair, error) {
+	access, err := t.sign(user, accessType, t.accessTTL)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	refresh, err := t.sign(user, refreshType, t.refreshTTL)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+	return models.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(t.accessTTL.Seconds()),
+	}, nil
+}
+
+// Parse valida un token de acceso y devuelve su usuario.
+func (t *Tokens) Parse(token string) (models.User, error) {
+	user, _, err := t.parse(token, accessType)
+	return user, err
+}
+
+// ParseRefresh valida un token de renovación y devuelve su usuario y cuándo se emitió.
+func (t *Tokens) ParseRefresh(token string) (models.User, time.Time, error) {
+	return t.parse(token, refreshType)
+}
+
+func (t *Tokens) sign(user models.User, typ string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(t.method, claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.Username,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		UserID:    user.ID,
+		Role:      user.Role,
+		DentistID: user.DentistID,
+		Type:      typ,
+	})
+	return token.SignedString(t.signKey)
+}
+
+func (t *Tokens) parse(token, typ string) (models.User, time.Time, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return t.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{t.method.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return models.User{}, time.Time{}, domain.Unauthorized("Token expired")
+	case err != nil:
+		return models.User{}, time.Time{}, domain.Unauthorized("Invalid token")
+	case c.Type != typ || c.Subject == "" || c.IssuedAt == nil || !ValidRole(c.Role):
+		return models.User{}, time.Time{}, domain.Unauthorized("Invalid token")
+	case c.Role == RoleDentist && c.DentistID == 0:
+		return models.User{}, time.Time{}, domain.Unauthorized("Invalid token")
+	}
+	user := models.User{ID: c.UserID, Username: c.Subject, Role: c.Role, DentistID: c.DentistID}
+	return user, c.IssuedAt.Time, nil
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func mustTokens(t *testing.T, config Config) *Tokens {
+	t.Helper()
+	tokens, err := NewTokens(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func TestNewTokens(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		config Config
+		ok     bool
+	}{
+		{"HS256 by default", Config{Secret: secret}, true},
+		{"short secret", Config{Algorithm: HS256, Secret: secret[:31]}, false},
+		{"RS256", Config{Algorithm: RS256, PrivateKey: key}, true},
+		{"RS256 without key", Config{Algorithm: RS256}, false},
+		{"unsupported algorithm", Config{Algorithm: "none", Secret: secret}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens, err := NewTokens(test.config)
+			if (err == nil) != test.ok {
+				t.Fatalf("NewTokens() error = %v, want ok = %v", err, test.ok)
+			}
+			if err != nil {
+				return
+			}
+			if tokens.accessTTL != DefaultAccessTTL || tokens.refreshTTL != DefaultRefreshTTL {
+				t.Errorf("TTLs = %v, %v, want the defaults", tokens.accessTTL, tokens.refreshTTL)
+			}
+			pair, err := tokens.Issue(models.User{Username: "ana", Role: RoleAdmin})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tokens.Parse(pair.AccessToken); err != nil {
+				t.Errorf("Parse() = %v", err)
+			}
+		})
+	}
+}
+
+func TestIssueAndParse(t *testing.T) {
+	tokens := mustTokens(t, Config{Secret: secret})
+	user := models.User{ID: 7, Username: "doctor", Role: RoleDentist, DentistID: 3}
+
+	pair, err := tokens.Issue(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pair.TokenType != "Bearer" || pair.ExpiresIn != int(DefaultAccessTTL.Seconds()) {
+		t.Errorf("pair = %+v, want a Bearer token that expires in %v", pair, DefaultAccessTTL)
+	}
+
+	got, err := tokens.Parse(pair.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, user) {
+		t.Errorf("Parse() = %+v, want %+v", got, user)
+	}
+	got, issuedAt, err := tokens.ParseRefresh(pair.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, user) || time.Since(issuedAt) > time.Minute {
+		t.Errorf("ParseRefresh() = %+v issued at %v, want %+v issued now", got, issuedAt, user)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tokens := mustTokens(t, Config{Secret: secret})
+	admin := models.User{Username: "ana", Role: RoleAdmin}
+	pair, err := tokens.Issue(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := mustTokens(t, Config{Secret: secret, AccessTTL: -time.Minute, RefreshTTL: -time.Minute})
+	expiredPair, err := expired.Issue(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := mustTokens(t, Config{Secret: []byte(strings.Repeat("x", 32))})
+	otherPair, err := other.Issue(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unscoped, err := tokens.sign(models.User{Username: "doctor", Role: RoleDentist}, accessType, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownRole, err := tokens.sign(models.User{Username: "ana", Role: "owner"}, accessType, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		parse   func(string) error
+		token   string
+		message string
+	}{
+		{"refresh token as access token", parseAccess(tokens), pair.RefreshToken, "Invalid token"},
+		{"access token as refresh token", parseRefresh(tokens), pair.AccessToken, "Invalid token"},
+		{"expired access token", parseAccess(tokens), expiredPair.AccessToken, "Token expired"},
+		{"expired refresh token", parseRefresh(tokens), expiredPair.RefreshToken, "Token expired"},
+		{"another secret", parseAccess(tokens), otherPair.AccessToken, "Invalid token"},
+		{"tampered", parseAccess(tokens), pair.AccessToken[:len(pair.AccessToken)-2] + "xx", "Invalid token"},
+		{"garbage", parseAccess(tokens), "not-a-token", "Invalid token"},
+		{"dentist without dentist", parseAccess(tokens), unscoped, "Invalid token"},
+		{"unknown role", parseAccess(tokens), unknownRole, "Invalid token"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.parse(test.token)
+			var failed *domain.Error
+			if !errors.Is(err, domain.ErrUnauthorized) || !errors.As(err, &failed) || failed.Message != test.message {
+				t.Errorf("error = %v, want unauthorized %q", err, test.message)
+			}
+		})
+	}
+}
+
+func parseAccess(tokens *Tokens) func(string) error {
+	return func(token string) error {
+		_, err := tokens.Parse(token)
+		return err
+	}
+}
+
+func parseRefresh(tokens *Tokens) func(string) error {
+	return func(token string) error {
+		_, _, err := tokens.ParseRefresh(token)
+		return err
+	}
+}
@@ -0,0 +1,126 @@
+package availability
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"time"
+)
+
+// span es un intervalo [start, end) expresado en minutos desde la medianoche.
+type span struct {
+	start int
+	end   int
+}
+
+// FreeSlots calcula los horarios libres de un dentista entre dos fechas (inclusive) para turnos
+// de la duración indicada, a partir de su agenda semanal y de los turnos ya reservados.
+func FreeSlots(repos repository.Repositories, dentistID int, from, to time.Time, duration int, now time.Time) ([]models.AvailableSlot, error) {
+	slots := []models.AvailableSlot{}
+
+	blocks, err := repos.Schedules().Get(dentistID)
+	if err != nil || len(blocks) == 0 {
+		return slots, err
+	}
+
+	busy, err := bookedSlots(repos, dentistID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, block := range blocks {
+			if block.DayOfWeek != int(day.Weekday()) {
+				continue
+			}
+
+			free := []span{clockSpan(block.StartTime, block.EndTime)}
+			for _, pause := range block.Breaks {
+				free = subtract(free, clockSpan(pause.StartTime, pause.EndTime))
+			}
+			for _, booked := range busy {
+				free = subtract(free, daySpan(day, booked))
+			}
+
+			for _, interval := range free {
+				for start := interval.start; start+duration <= interval.end; start += duration {
+					startAt := day.Add(time.Duration(start) * time.Minute)
+					if startAt.Before(now) {
+						continue
+					}
+					slots = append(slots, models.AvailableSlot{
+						DentistID: dentistID,
+						Date:      day.Format(timeslot.DateLayout),
+						StartTime: startAt.Format(timeslot.TimeLayout),
+						EndTime:   startAt.Add(time.Duration(duration) * time.Minute).Format(timeslot.TimeLayout),
+					})
+				}
+			}
+		}
+	}
+
+	return slots, nil
+}
+
+// bookedSlots devuelve los turnos del dentista en el rango. Se incluye el día anterior
+// para contemplar turnos que terminan después de la medianoche.
+func bookedSlots(repos repository.Repositories, dentistID int, from, to time.Time) ([]timeslot.Slot, error) {
+	// Ningún turno tiene paciente 0, así que solo se obtienen los del dentista
+	booked, err := repos.Appointments().Booked(dentistID, 0,
+		from.AddDate(0, 0, -1).Format(timeslot.DateLayout), to.Format(timeslot.DateLayout), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []timeslot.Slot
+	for _, appointment := range booked {
+		slot, err := timeslot.New(appointment.Date, appointment.Time, time.Duration(appointment.Duration)*time.Minute)
+		if err != nil {
+			continue
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// clockSpan convierte un par de horas HH:MM ya validadas en un intervalo.
+func clockSpan(startTime, endTime string) span {
+	start, _ := timeslot.ClockMinutes(startTime)
+	end, _ := timeslot.ClockMinutes(endTime)
+	return span{start, end}
+}
+
+// daySpan recorta un turno al día indicado y lo expresa en minutos desde la medianoche.
+func daySpan(day time.Time, slot timeslot.Slot) span {
+	start := int(slot.Start.Sub(day).Minutes())
+	end := int(slot.End.Sub(day).Minutes())
+	if start < 0 {
+		start = 0
+	}
+	if end > 24*60 {
+		end = 24 * 60
+	}
+	return span{start, end}
+}
+
+// subtract quita un intervalo de una lista de intervalos libres.
+func subtract(free []span, taken span) []span {
+	if taken.start >= taken.end {
+		return free
+	}
+
+	var result []span
+	for _, interval := range free {
+		if taken.end <= interval.start || taken.start >= interval.end {
+			result = append(result, interval)
+			continue
+		}
+		if taken.start > interval.start {
+			result = append(result, span{interval.start, taken.start})
+		}
+		if taken.end < interval.end {
+			result = append(result, span{taken.end, interval.end})
+		}
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+package availability
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestStore crea dos dentistas. El primero atiende los lunes (2030-01-07 es lunes) de
+// 09:00 a 12:00 con una pausa de 10:30 a 11:00 y los martes de 00:00 a 01:00; el segundo no
+// tiene agenda.
+func newTestStore(t *testing.T) *memory.Store {
+	t.Helper()
+	store := memory.New()
+	for _, dentist := range []models.Dentist{
+		{LastName: "Perez", FirstName: "Juan", License: "MP-1"},
+		{LastName: "Gomez", FirstName: "Ana", License: "MP-2"},
+	} {
+		if err := store.Dentists().Create(&dentist); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blocks := []models.ScheduleBlock{
+		{DayOfWeek: 1, StartTime: "09:00", EndTime: "12:00", Breaks: []models.ScheduleBreak{{StartTime: "10:30", EndTime: "11:00"}}},
+		{DayOfWeek: 2, StartTime: "00:00", EndTime: "01:00"},
+	}
+	if err := store.Schedules().Replace(1, blocks); err != nil {
+		t.Fatal(err)
+	}
+	for _, appointment := range []models.Appointment{
+		{Date: "2030-01-07", Time: "09:30", Duration: 30, Status: "scheduled", PatientID: 1, DentistID: 1},
+		// Los cancelados no ocupan horario
+		{Date: "2030-01-07", Time: "11:00", Duration: 30, Status: "cancelled", PatientID: 1, DentistID: 1},
+		// Termina el martes a las 00:30
+		{Date: "2030-01-07", Time: "23:30", Duration: 60, Status: "confirmed", PatientID: 2, DentistID: 1},
+		{Date: "2030-01-07", Time: "11:30", Duration: 30, Status: "scheduled", PatientID: 1, DentistID: 2},
+	} {
+		if err := store.Appointments().Create(&appointment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func date(t *testing.T, value string) time.Time {
+	t.Helper()
+	day, err := time.ParseInLocation("2006-01-02 15:04", value, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return day
+}
+
+func TestFreeSlots(t *testing.T) {
+	store := newTestStore(t)
+	tests := []struct {
+		name      string
+		dentistID int
+		from, to  string
+		duration  int
+		now       string
+		want      []string
+	}{
+		{"breaks and booked appointments", 1, "2030-01-07", "2030-01-08", 30, "2030-01-01 00:00",
+			[]string{"2030-01-07 09:00-09:30", "2030-01-07 10:00-10:30", "2030-01-07 11:00-11:30", "2030-01-07 11:30-12:00", "2030-01-08 00:30-01:00"}},
+		{"longer appointments", 1, "2030-01-07", "2030-01-08", 60, "2030-01-01 00:00",
+			[]string{"2030-01-07 11:00-12:00"}},
+		{"shorter appointments", 1, "2030-01-07", "2030-01-08", 20, "2030-01-01 00:00",
+			[]string{"2030-01-07 09:00-09:20", "2030-01-07 10:00-10:20", "2030-01-07 11:00-11:20", "2030-01-07 11:20-11:40", "2030-01-07 11:40-12:00", "2030-01-08 00:30-00:50"}},
+		{"past slots", 1, "2030-01-07", "2030-01-08", 30, "2030-01-07 10:15",
+			[]string{"2030-01-07 11:00-11:30", "2030-01-07 11:30-12:00", "2030-01-08 00:30-01:00"}},
+		{"days without schedule", 1, "2030-01-09", "2030-01-13", 30, "2030-01-01 00:00", []string{}},
+		{"dentist without schedule", 2, "2030-01-07", "2030-01-08", 30, "2030-01-01 00:00", []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slots, err := FreeSlots(store, test.dentistID, date(t, test.from+" 00:00"), date(t, test.to+" 00:00"), test.duration, date(t, test.now))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, slot := range slots {
+				if slot.DentistID != test.dentistID {
+					t.Errorf("slot %+v belongs to another dentist", slot)
+				}
+				got = append(got, slot.Date+" "+slot.StartTime+"-"+slot.EndTime)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("FreeSlots() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestService(t *testing.T) {
+	service := NewService(newTestStore(t))
+
+	slots, err := service.ForDentist(1, Query{From: "2030-01-07", To: "2030-01-07", Duration: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 1 || slots[0].StartTime != "11:00" {
+		t.Errorf("ForDentist() = %+v, want only 11:00", slots)
+	}
+	// Sin duración se usa la duración por defecto
+	all, err := service.All(Query{From: "2030-01-07", To: "2030-01-08"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Errorf("All() = %+v, want the 5 slots of the first dentist", all)
+	}
+	if _, err := service.ForDentist(9, Query{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing dentist: error = %v, want not found", err)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		field string
+	}{
+		{"invalid from", Query{From: "07/01/2030"}, "from"},
+		{"invalid duration", Query{Duration: models.MaxDuration + 1}, "duration"},
+		{"to before from", Query{From: "2030-01-07", To: "2030-01-06"}, "to"},
+		{"range too long", Query{From: "2030-01-01", To: "2030-02-01"}, "to"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := service.ForDentist(1, test.query)
+			var invalid *domain.ValidationError
+			if !errors.As(err, &invalid) || invalid.Fields[0].Field != test.field {
+				t.Fatalf("error = %v, want a validation error on %s", err, test.field)
+			}
+		})
+	}
+	if _, err := service.All(Query{From: "2030-01-01", To: "2030-01-31"}); err != nil {
+		t.Errorf("a range of %d days: %v", MaxRangeDays, err)
+	}
+}
@@ -0,0 +1,92 @@
+package availability
+
+import (
+	"encoding/json"
+	"net/http"
+	"odontology-appointments/internal/web"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GET: Horarios libres de un dentista
+// @Summary Buscar horarios libres de un dentista
+// @Tags Disponibilidad
+// @Produce json
+// @Param id path int true "ID del dentista"
+// @Param from query string false "Fecha desde (YYYY-MM-DD), por defecto hoy"
+// @Param to query string false "Fecha hasta (YYYY-MM-DD), por defecto una semana después de from"
+// @Param duration query int false "Duración del turno en minutos"
+// @Success 200 {array} models.AvailableSlot
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /dentists/{id}/availability [get]
+func GetDentistAvailability(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		query, err := parseQuery(r)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		slots, err := service.ForDentist(id, query)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(slots)
+	}
+}
+
+// GET: Horarios libres de todos los dentistas
+// @Summary Buscar horarios libres de todos los dentistas
+// @Tags Disponibilidad
+// @Produce json
+// @Param from query string false "Fecha desde (YYYY-MM-DD), por defecto hoy"
+// @Param to query string false "Fecha hasta (YYYY-MM-DD), por defecto una semana después de from"
+// @Param duration query int false "Duración del turno en minutos"
+// @Success 200 {array} models.AvailableSlot
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /availability [get]
+func GetAvailability(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := parseQuery(r)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		slots, err := service.All(query)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(slots)
+	}
+}
+
+// parseQuery lee de la query la búsqueda de horarios libres. Los valores se validan en el
+// servicio.
+func parseQuery(r *http.Request) (Query, error) {
+	query := Query{From: r.URL.Query().Get("from"), To: r.URL.Query().Get("to")}
+	var err error
+	query.Duration, err = web.QueryInt(r, "duration")
+	return query, err
+}
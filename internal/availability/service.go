@@ -0,0 +1,101 @@
+package availability
+
+import (
+	"fmt"
+	"odontology-appointments/internal/appointment"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"time"
+)
+
+// MaxRangeDays es la cantidad máxima de días que se pueden consultar en una búsqueda.
+const MaxRangeDays = 31
+
+// Query es una búsqueda de horarios libres. Los campos vacíos toman los valores por defecto:
+// desde hoy, una semana y turnos de la duración por defecto.
+type Query struct {
+	From     string `json:"from" validate:"date"`
+	To       string `json:"to" validate:"date"`
+	Duration int    `json:"duration" validate:"duration"`
+}
+
+// Service calcula los horarios libres de los dentistas. Devuelve errores de dominio (ver
+// paquete domain) y no depende de HTTP.
+type Service struct {
+	store repository.Store
+}
+
+// NewService crea un servicio de disponibilidad sobre el almacenamiento indicado.
+func NewService(store repository.Store) *Service {
+	return &Service{store: store}
+}
+
+// ForDentist devuelve los horarios libres del dentista.
+func (s *Service) ForDentist(dentistID int, query Query) ([]models.AvailableSlot, error) {
+	now := time.Now()
+	from, to, duration, err := query.resolve(now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.store.Dentists().Get(dentistID); err == repository.ErrNotFound {
+		return nil, domain.NotFound("Dentist not found")
+	} else if err != nil {
+		return nil, err
+	}
+	return FreeSlots(s.store, dentistID, from, to, duration, now)
+}
+
+// All devuelve los horarios libres de todos los dentistas con agenda.
+func (s *Service) All(query Query) ([]models.AvailableSlot, error) {
+	now := time.Now()
+	from, to, duration, err := query.resolve(now)
+	if err != nil {
+		return nil, err
+	}
+	dentistIDs, err := s.store.Schedules().DentistIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []models.AvailableSlot{}
+	for _, dentistID := range dentistIDs {
+		free, err := FreeSlots(s.store, dentistID, from, to, duration, now)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, free...)
+	}
+	return slots, nil
+}
+
+// resolve valida la búsqueda y devuelve el rango de fechas y la duración aplicando los
+// valores por defecto.
+func (q Query) resolve(now time.Time) (time.Time, time.Time, int, error) {
+	if err := validation.Check(q); err != nil {
+		return time.Time{}, time.Time{}, 0, err
+	}
+
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if q.From != "" {
+		from, _ = time.ParseInLocation(timeslot.DateLayout, q.From, time.Local)
+	}
+	to := from.AddDate(0, 0, 6)
+	if q.To != "" {
+		to, _ = time.ParseInLocation(timeslot.DateLayout, q.To, time.Local)
+	}
+	if to.Before(from) {
+		return from, to, 0, domain.FieldError("to", "must not be before from")
+	}
+	if to.After(from.AddDate(0, 0, MaxRangeDays-1)) {
+		return from, to, 0, domain.FieldError("to", fmt.Sprintf("must be at most %d days after from", MaxRangeDays-1))
+	}
+
+	duration := q.Duration
+	if duration == 0 {
+		duration = appointment.DefaultDuration
+	}
+	return from, to, duration, nil
+}
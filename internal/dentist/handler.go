@@ -1,9 +1,12 @@
 package dentist
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/security"
+	"odontology-appointments/internal/web"
 	"odontology-appointments/pkg/models"
 	"strconv"
 
@@ -11,44 +14,41 @@ import (
 )
 
 // @Summary Listar todos los dentistas
+// @Description Devuelve una página de dentistas. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.
 // @Tags Dentista
 // @Produce json
+// @Param last_name query string false "Apellido o comienzo del apellido, sin distinguir mayúsculas"
+// @Param include_deleted query bool false "Incluir los dentistas eliminados (requiere el permiso dentists:admin)"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Param sort query string false "Campos de orden separados por comas, con - para orden descendente: id, last_name, first_name, license"
 // @Success 200 {array} models.Dentist
+// @Header 200 {integer} X-Total-Count "Total de registros sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /dentistas [get]
-func GetAllDentists(db *sql.DB) http.HandlerFunc {
+func GetAllDentists(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Consulta a la base de datos para obtener todos los dentistas
-		rows, err := db.Query("SELECT id, last_name, first_name, license FROM dentists")
+		page, err := web.PageQuery(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
-		defer rows.Close()
-
-		// Crear una lista para almacenar los dentistas
-		var dentists []models.Dentist
-
-		// Iterar sobre los resultados y agregar cada dentista a la lista
-		for rows.Next() {
-			var dentist models.Dentist
-			if err := rows.Scan(&dentist.ID, &dentist.LastName, &dentist.FirstName, &dentist.License); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-			dentists = append(dentists, dentist)
-		}
-
-		// Verificar si hubo un error al iterar
-		if err := rows.Err(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		includeDeleted, ok := security.IncludeDeleted(w, r, auth.DentistsAdmin)
+		if !ok {
 			return
 		}
+		filter := repository.DentistFilter{LastName: r.URL.Query().Get("last_name"), IncludeDeleted: includeDeleted}
 
-		// Configurar el encabezado de la respuesta y codificar la lista de dentistas como JSON
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(dentists); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		dentists, total, err := service.List(filter, page)
+		if err != nil {
+			web.Fail(w, err)
+			return
 		}
+		web.List(w, r, page, total, dentists)
 	}
 }
 
@@ -59,30 +59,27 @@ func GetAllDentists(db *sql.DB) http.HandlerFunc {
 // @Produce json
 // @Param dentista body models.Dentist true "Dentista"
 // @Success 201 {object} models.Dentist
-// @Failure 404 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 409 {object} models.DuplicateError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /dentistas [post]
-func CreateDentist(db *sql.DB) http.HandlerFunc {
+func CreateDentist(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var dentist models.Dentist
 		err := json.NewDecoder(r.Body).Decode(&dentist)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			web.DecodeError(w, err)
 			return
 		}
 
-		stmt, err := db.Prepare("INSERT INTO dentists (last_name, first_name, license) VALUES (?, ?, ?)")
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		res, err := stmt.Exec(dentist.LastName, dentist.FirstName, dentist.License)
+		dentist, err = service.Create(dentist, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		id, _ := res.LastInsertId()
-		dentist.ID = int(id)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(dentist)
 	}
@@ -94,26 +91,23 @@ func CreateDentist(db *sql.DB) http.HandlerFunc {
 // @Produce json
 // @Param id path int true "ID del dentista"
 // @Success 200 {object} models.Dentist
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /dentistas/{id} [get]
-func GetDentistByID(db *sql.DB) http.HandlerFunc {
+func GetDentistByID(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
 
-		var dentist models.Dentist
-		err = db.QueryRow("SELECT * FROM dentists WHERE id = ?", id).Scan(
-			&dentist.ID, &dentist.LastName, &dentist.FirstName, &dentist.License)
+		dentist, err := service.Get(id)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				http.Error(w, "Dentist not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			web.Fail(w, err)
 			return
 		}
 
@@ -130,33 +124,32 @@ func GetDentistByID(db *sql.DB) http.HandlerFunc {
 // @Param id path int true "ID del dentista"
 // @Param dentista body models.Dentist true "Dentista"
 // @Success 200 {object} models.Dentist
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.DuplicateError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /dentistas/{id} [put]
-func UpdateDentist(db *sql.DB) http.HandlerFunc {
+func UpdateDentist(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
 
 		var dentist models.Dentist
 		err = json.NewDecoder(r.Body).Decode(&dentist)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			web.DecodeError(w, err)
 			return
 		}
 
-		stmt, err := db.Prepare("UPDATE dentists SET last_name = ?, first_name = ?, license = ? WHERE id = ?")
+		dentist, err = service.Update(id, dentist, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		_, err = stmt.Exec(dentist.LastName, dentist.FirstName, dentist.License, id)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
@@ -172,66 +165,28 @@ func UpdateDentist(db *sql.DB) http.HandlerFunc {
 // @Produce json
 // @Param id path int true "ID del dentista"
 // @Success 200 {object} models.Dentist
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.DuplicateError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /dentistas/{id} [patch]
-func PartialUpdateDentist(db *sql.DB) http.HandlerFunc {
+func PartialUpdateDentist(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
 
-		var fields map[string]interface{}
-		err = json.NewDecoder(r.Body).Decode(&fields)
+		// Los campos recibidos se aplican sobre el dentista actual y se valida el resultado
+		_, err = service.Patch(id, func(dentist *models.Dentist) error {
+			return web.Decode(r, dentist)
+		}, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		query := "UPDATE dentists SET "
-		args := []interface{}{}
-		first := true
-
-		if lastName, ok := fields["last_name"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "last_name = ?"
-			args = append(args, lastName)
-			first = false
-		}
-
-		if firstName, ok := fields["first_name"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "first_name = ?"
-			args = append(args, firstName)
-			first = false
-		}
-
-		if license, ok := fields["license"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "license = ?"
-			args = append(args, license)
-		}
-
-		query += " WHERE id = ?"
-		args = append(args, id)
-
-		stmt, err := db.Prepare(query)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		_, err = stmt.Exec(args...)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
@@ -241,33 +196,73 @@ func PartialUpdateDentist(db *sql.DB) http.HandlerFunc {
 
 // DELETE: Eliminar dentista
 // @Summary Eliminar un dentista
+// @Description Es una baja lógica: el dentista deja de aparecer en los listados y puede restaurarse.
+// @Description La política indica qué hacer con sus turnos reservados o confirmados: restrict (por
+// @Description defecto) rechaza la eliminación si tiene alguno, cascade elimina todos sus turnos y
+// @Description reassign los pasa al dentista reassign_to, que debe tener el horario libre y de atención.
 // @Tags Dentista
 // @Produce json
 // @Param id path int true "ID del dentista"
+// @Param policy query string false "restrict, cascade o reassign"
+// @Param reassign_to query int false "ID del dentista que recibe los turnos (policy=reassign)"
 // @Success 204
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /dentistas/{id} [delete]
-func DeleteDentist(db *sql.DB) http.HandlerFunc {
+func DeleteDentist(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		// Un reassign_to inválido queda en 0 y lo rechaza el servicio
+		options := DeleteOptions{Policy: r.URL.Query().Get("policy")}
+		options.ReassignTo, _ = strconv.Atoi(r.URL.Query().Get("reassign_to"))
+
+		if err := service.Delete(id, options, security.Actor(r)); err != nil {
+			web.Fail(w, err)
 			return
 		}
 
-		stmt, err := db.Prepare("DELETE FROM dentists WHERE id = ?")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// POST: Restaurar dentista
+// @Summary Restaurar un dentista eliminado
+// @Description También restaura los turnos que se eliminaron con él, salvo los de pacientes eliminados. Falla si otro dentista activo tiene la misma matrícula.
+// @Tags Dentista
+// @Produce json
+// @Param id path int true "ID del dentista"
+// @Success 200 {object} models.Dentist
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /dentistas/{id}/restore [post]
+func RestoreDentist(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
 
-		_, err = stmt.Exec(id)
+		dentist, err := service.Restore(id, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		web.JSON(w, http.StatusOK, dentist)
 	}
 }
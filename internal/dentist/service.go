@@ -0,0 +1,249 @@
+package dentist
+
+import (
+	"fmt"
+	"odontology-appointments/internal/appointment"
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"time"
+)
+
+// Service concentra las reglas de negocio de los dentistas. Devuelve errores de dominio
+// (ver paquete domain) y no depende de HTTP. Los cambios se registran en la auditoría a
+// nombre del actor que recibe cada método.
+type Service struct {
+	store        repository.Store
+	appointments *appointment.Service
+}
+
+// NewService crea un servicio de dentistas sobre el almacenamiento indicado. appointments
+// aplica las reglas de reserva a los turnos que se reasignan al eliminar un dentista.
+func NewService(store repository.Store, appointments *appointment.Service) *Service {
+	return &Service{store: store, appointments: appointments}
+}
+
+// DeleteOptions indica qué hacer con los turnos del dentista que se elimina.
+type DeleteOptions struct {
+	// Policy es appointment.PolicyRestrict (por defecto), PolicyCascade o PolicyReassign.
+	Policy string
+	// ReassignTo es el dentista que recibe los turnos con PolicyReassign.
+	ReassignTo int
+}
+
+// List devuelve una página de los dentistas que cumplen el filtro y el total sin paginar.
+func (s *Service) List(filter repository.DentistFilter, page repository.Page) ([]models.Dentist, int, error) {
+	if err := page.Check(repository.DentistSortFields); err != nil {
+		return nil, 0, err
+	}
+	return s.store.Dentists().Find(filter, page)
+}
+
+// Get devuelve un dentista por ID.
+func (s *Service) Get(id int) (models.Dentist, error) {
+	dentist, err := s.store.Dentists().Get(id)
+	return dentist, notFound(err)
+}
+
+// Create valida y guarda un dentista nuevo.
+func (s *Service) Create(dentist models.Dentist, actor string) (models.Dentist, error) {
+	dentist.DeletedAt = ""
+	if err := validation.Check(dentist); err != nil {
+		return dentist, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return dentist, err
+	}
+	defer tx.Rollback()
+
+	if err := checkLicense(tx, dentist.License, 0); err != nil {
+		return dentist, err
+	}
+	if err := tx.Dentists().Create(&dentist); err != nil {
+		return dentist, err
+	}
+	if err := audit.Record(tx, actor, audit.ResourceDentist, dentist.ID, audit.ActionCreate, nil, dentist); err != nil {
+		return dentist, err
+	}
+	return dentist, tx.Commit()
+}
+
+// Update reemplaza todos los datos de un dentista.
+func (s *Service) Update(id int, dentist models.Dentist, actor string) (models.Dentist, error) {
+	return s.patch(id, func(current *models.Dentist) error {
+		*current = dentist
+		return nil
+	}, audit.ActionUpdate, actor)
+}
+
+// Patch aplica apply sobre el dentista actual y guarda el resultado si es válido. Si apply
+// falla se devuelve su error sin cambios.
+func (s *Service) Patch(id int, apply func(*models.Dentist) error, actor string) (models.Dentist, error) {
+	return s.patch(id, apply, audit.ActionPatch, actor)
+}
+
+// patch es la parte común de Update y Patch. action es la acción que se registra en la
+// auditoría.
+func (s *Service) patch(id int, apply func(*models.Dentist) error, action, actor string) (models.Dentist, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Dentist{}, err
+	}
+	defer tx.Rollback()
+
+	current, err := tx.Dentists().Get(id)
+	if err != nil {
+		return current, notFound(err)
+	}
+	dentist := current
+	if err := apply(&dentist); err != nil {
+		return dentist, err
+	}
+	dentist.ID = id
+	dentist.DeletedAt = ""
+
+	if err := validation.Check(dentist); err != nil {
+		return dentist, err
+	}
+	if err := checkLicense(tx, dentist.License, id); err != nil {
+		return dentist, err
+	}
+	if err := tx.Dentists().Update(dentist); err != nil {
+		return dentist, notFound(err)
+	}
+	if err := audit.Record(tx, actor, audit.ResourceDentist, id, action, current, dentist); err != nil {
+		return dentist, err
+	}
+	return dentist, tx.Commit()
+}
+
+// Delete da de baja un dentista. Sus turnos reservados o confirmados se resuelven según la
+// política indicada; los que llegaron a un estado final no impiden la baja y, salvo con
+// cascade, quedan a su nombre. Su agenda y sus series se conservan por si se restaura.
+func (s *Service) Delete(id int, options DeleteOptions, actor string) error {
+	switch options.Policy {
+	case "":
+		options.Policy = appointment.PolicyRestrict
+	case appointment.PolicyRestrict, appointment.PolicyCascade:
+	case appointment.PolicyReassign:
+		if options.ReassignTo <= 0 || options.ReassignTo == id {
+			return domain.FieldError("reassign_to", "must be the ID of another dentist")
+		}
+	default:
+		return domain.Invalid("Invalid policy, expected restrict, cascade or reassign")
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	dentist, err := tx.Dentists().Get(id)
+	if err != nil {
+		return notFound(err)
+	}
+
+	deletedAt := time.Now().UTC().Format(time.RFC3339)
+	owned := repository.AppointmentFilter{DentistID: id}
+	switch options.Policy {
+	case appointment.PolicyRestrict:
+		appointments, err := appointment.Open(tx, owned)
+		if err != nil {
+			return err
+		}
+		if len(appointments) > 0 {
+			return domain.Conflict("Dentist has %d scheduled or confirmed appointments, use policy=cascade or policy=reassign", len(appointments))
+		}
+	case appointment.PolicyCascade:
+		if err := appointment.DeleteAll(tx, owned, deletedAt, actor); err != nil {
+			return err
+		}
+	case appointment.PolicyReassign:
+		if _, err := tx.Dentists().Get(options.ReassignTo); err != nil {
+			if err == repository.ErrNotFound {
+				return domain.FieldError("reassign_to", fmt.Sprintf("Dentist %d does not exist", options.ReassignTo))
+			}
+			return err
+		}
+		if err := s.appointments.ReassignDentist(tx, id, options.ReassignTo, actor); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Dentists().Delete(id, deletedAt); err != nil {
+		return err
+	}
+	deleted := dentist
+	deleted.DeletedAt = deletedAt
+	if err := audit.Record(tx, actor, audit.ResourceDentist, id, audit.ActionDelete, dentist, deleted); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Restore vuelve a activar un dentista eliminado junto con los turnos que se eliminaron con
+// él (policy=cascade), salvo los de pacientes que sigan eliminados.
+func (s *Service) Restore(id int, actor string) (models.Dentist, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Dentist{}, err
+	}
+	defer tx.Rollback()
+
+	dentist, err := tx.Dentists().GetDeleted(id)
+	if err != nil {
+		return dentist, deletedNotFound(err)
+	}
+	if err := checkLicense(tx, dentist.License, id); err != nil {
+		return dentist, err
+	}
+	if err := tx.Dentists().Restore(id); err != nil {
+		return dentist, err
+	}
+	restored := dentist
+	restored.DeletedAt = ""
+	if err := audit.Record(tx, actor, audit.ResourceDentist, id, audit.ActionRestore, dentist, restored); err != nil {
+		return dentist, err
+	}
+	owned := repository.AppointmentFilter{DentistID: id, DeletedAt: dentist.DeletedAt}
+	if err := appointment.RestoreAll(tx, owned, actor); err != nil {
+		return dentist, err
+	}
+	dentist.DeletedAt = ""
+	return dentist, tx.Commit()
+}
+
+// notFound traduce repository.ErrNotFound al error de dominio de un dentista inexistente.
+func notFound(err error) error {
+	if err == repository.ErrNotFound {
+		return domain.NotFound("Dentist not found")
+	}
+	return err
+}
+
+// deletedNotFound traduce repository.ErrNotFound al error de dominio de un dentista que no
+// existe o no está eliminado.
+func deletedNotFound(err error) error {
+	if err == repository.ErrNotFound {
+		return domain.NotFound("Deleted dentist not found")
+	}
+	return err
+}
+
+// checkLicense devuelve un *domain.DuplicateError si otro dentista ya tiene la misma
+// matrícula. excludeID es el registro que se está modificando, o 0 al crear.
+func checkLicense(repos repository.Repositories, license string, excludeID int) error {
+	existing, err := repos.Dentists().GetByLicense(license)
+	if err == repository.ErrNotFound || (err == nil && existing.ID == excludeID) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &domain.DuplicateError{Resource: "Dentist", Field: "license", Value: license, ExistingID: existing.ID}
+}
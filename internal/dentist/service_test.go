@@ -0,0 +1,199 @@
+package dentist
+
+import (
+	"errors"
+	"odontology-appointments/internal/appointment"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"testing"
+)
+
+const monday = "2030-01-07"
+
+// fixture es un Store en memoria con los dentistas 1 y 2, que atienden los lunes de 9 a 13,
+// el dentista 3 sin horarios y los pacientes 1 y 2.
+type fixture struct {
+	store        *memory.Store
+	dentists     *Service
+	appointments *appointment.Service
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	store := memory.New()
+	for i, license := range []string{"MP-1", "MP-2", "MP-3"} {
+		dentist := models.Dentist{LastName: "Dentist", FirstName: license, License: license}
+		mustDo(t, store.Dentists().Create(&dentist))
+		if i < 2 {
+			mustDo(t, store.Schedules().Replace(dentist.ID, []models.ScheduleBlock{{DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00"}}))
+		}
+	}
+	for _, dni := range []string{"30111222", "30111333"} {
+		patient := models.Patient{LastName: "Patient", FirstName: dni, Address: "Calle 1", DNI: dni, RegistrationDate: "2024-01-01"}
+		mustDo(t, store.Patients().Create(&patient))
+	}
+	appointments := appointment.NewService(store, appointment.Options{})
+	return fixture{store: store, dentists: NewService(store, appointments), appointments: appointments}
+}
+
+// book crea un turno de 30 minutos y lo lleva al estado indicado.
+func (f fixture) book(t *testing.T, clock string, patientID, dentistID int, status string) models.Appointment {
+	t.Helper()
+	booked, err := f.appointments.Create(models.Appointment{Date: monday, Time: clock, Duration: 30, PatientID: patientID, DentistID: dentistID}, "test")
+	mustDo(t, err)
+	if status != appointment.StatusScheduled {
+		booked, err = f.appointments.ChangeStatus(booked.ID, status, "test", "test")
+		mustDo(t, err)
+	}
+	return booked
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, f fixture)
+		options DeleteOptions
+		want    error
+		// dentists es el dentista de cada turno de la fixture después de la baja, en orden de
+		// ID; 0 indica que el turno quedó eliminado.
+		dentists []int
+	}{
+		{
+			name:    "restrict without appointments",
+			setup:   func(t *testing.T, f fixture) {},
+			options: DeleteOptions{},
+		},
+		{
+			name: "restrict with an open appointment",
+			setup: func(t *testing.T, f fixture) {
+				f.book(t, "09:00", 1, 1, appointment.StatusConfirmed)
+			},
+			options:  DeleteOptions{Policy: appointment.PolicyRestrict},
+			want:     domain.ErrConflict,
+			dentists: []int{1},
+		},
+		{
+			name: "restrict with closed appointments only",
+			setup: func(t *testing.T, f fixture) {
+				f.book(t, "09:00", 1, 1, appointment.StatusCompleted)
+				f.book(t, "10:00", 1, 1, appointment.StatusCancelled)
+			},
+			options:  DeleteOptions{Policy: appointment.PolicyRestrict},
+			dentists: []int{1, 1},
+		},
+		{
+			name: "cascade",
+			setup: func(t *testing.T, f fixture) {
+				f.book(t, "09:00", 1, 1, appointment.StatusScheduled)
+				f.book(t, "10:00", 2, 1, appointment.StatusCompleted)
+			},
+			options:  DeleteOptions{Policy: appointment.PolicyCascade},
+			dentists: []int{0, 0},
+		},
+		{
+			name: "reassign open appointments",
+			setup: func(t *testing.T, f fixture) {
+				f.book(t, "09:00", 1, 1, appointment.StatusScheduled)
+				f.book(t, "10:00", 2, 1, appointment.StatusCompleted)
+				f.book(t, "11:00", 2, 1, appointment.StatusConfirmed)
+			},
+			options:  DeleteOptions{Policy: appointment.PolicyReassign, ReassignTo: 2},
+			dentists: []int{2, 1, 2},
+		},
+		{
+			name: "reassign onto a booked slot",
+			setup: func(t *testing.T, f fixture) {
+				f.book(t, "09:00", 1, 1, appointment.StatusScheduled)
+				f.book(t, "09:00", 2, 2, appointment.StatusScheduled)
+			},
+			options:  DeleteOptions{Policy: appointment.PolicyReassign, ReassignTo: 2},
+			want:     domain.ErrConflict,
+			dentists: []int{1, 2},
+		},
+		{
+			name: "reassign outside working hours",
+			setup: func(t *testing.T, f fixture) {
+				f.book(t, "09:00", 1, 1, appointment.StatusScheduled)
+			},
+			options:  DeleteOptions{Policy: appointment.PolicyReassign, ReassignTo: 3},
+			want:     domain.ErrConflict,
+			dentists: []int{1},
+		},
+		{
+			name:    "reassign to itself",
+			setup:   func(t *testing.T, f fixture) {},
+			options: DeleteOptions{Policy: appointment.PolicyReassign, ReassignTo: 1},
+			want:    domain.ErrValidation,
+		},
+		{
+			name:    "reassign to a missing dentist",
+			setup:   func(t *testing.T, f fixture) {},
+			options: DeleteOptions{Policy: appointment.PolicyReassign, ReassignTo: 9},
+			want:    domain.ErrValidation,
+		},
+		{
+			name:    "unknown policy",
+			setup:   func(t *testing.T, f fixture) {},
+			options: DeleteOptions{Policy: "orphan"},
+			want:    domain.ErrInvalid,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture(t)
+			test.setup(t, f)
+
+			err := f.dentists.Delete(1, test.options, "test")
+			if test.want == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Fatalf("error = %v, want %v", err, test.want)
+			}
+
+			_, getErr := f.store.Dentists().Get(1)
+			if deleted := getErr == repository.ErrNotFound; deleted != (test.want == nil) {
+				t.Errorf("dentist deleted = %v, want %v", deleted, test.want == nil)
+			}
+
+			appointments, err := f.store.Appointments().List(repository.AppointmentFilter{IncludeDeleted: true})
+			mustDo(t, err)
+			if len(appointments) != len(test.dentists) {
+				t.Fatalf("got %d appointments, want %d", len(appointments), len(test.dentists))
+			}
+			for i, got := range appointments {
+				want := test.dentists[i]
+				if want == 0 {
+					if got.DeletedAt == "" {
+						t.Errorf("appointment %d was not deleted", got.ID)
+					}
+					continue
+				}
+				if got.DeletedAt != "" || got.DentistID != want {
+					t.Errorf("appointment %d has dentist %d (deleted at %q), want dentist %d", got.ID, got.DentistID, got.DeletedAt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreAfterCascade(t *testing.T) {
+	f := newFixture(t)
+	booked := f.book(t, "09:00", 1, 1, appointment.StatusScheduled)
+	mustDo(t, f.dentists.Delete(1, DeleteOptions{Policy: appointment.PolicyCascade}, "test"))
+
+	_, err := f.dentists.Restore(1, "test")
+	mustDo(t, err)
+	if _, err := f.appointments.Get(booked.ID); err != nil {
+		t.Errorf("appointment deleted in cascade was not restored: %v", err)
+	}
+}
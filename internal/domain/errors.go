@@ -0,0 +1,113 @@
+// Package domain define los errores que devuelven los servicios. No dependen de HTTP; la capa
+// web los traduce a respuestas (ver web.Fail).
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+// Categorías de error. Se comparan con errors.Is.
+var (
+	// ErrNotFound indica que el registro pedido no existe.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict indica que la operación choca con el estado actual de otro registro.
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid indica un pedido mal formado, por ejemplo un parámetro desconocido.
+	ErrInvalid = errors.New("invalid request")
+	// ErrValidation indica que uno o más campos no cumplen sus reglas.
+	ErrValidation = errors.New("validation failed")
+	// ErrUnprocessable indica un pedido bien formado que viola una regla de negocio.
+	ErrUnprocessable = errors.New("unprocessable")
+	// ErrUnauthorized indica que faltan credenciales o que no son válidas.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden indica que el usuario autenticado no tiene permiso para la operación.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Error es un error de dominio con un mensaje apto para el cliente. Kind es su categoría.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Kind }
+
+func newError(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFound devuelve un error de categoría ErrNotFound.
+func NotFound(format string, args ...interface{}) error {
+	return newError(ErrNotFound, format, args...)
+}
+
+// Conflict devuelve un error de categoría ErrConflict.
+func Conflict(format string, args ...interface{}) error {
+	return newError(ErrConflict, format, args...)
+}
+
+// Invalid devuelve un error de categoría ErrInvalid.
+func Invalid(format string, args ...interface{}) error {
+	return newError(ErrInvalid, format, args...)
+}
+
+// Unprocessable devuelve un error de categoría ErrUnprocessable.
+func Unprocessable(format string, args ...interface{}) error {
+	return newError(ErrUnprocessable, format, args...)
+}
+
+// Unauthorized devuelve un error de categoría ErrUnauthorized.
+func Unauthorized(format string, args ...interface{}) error {
+	return newError(ErrUnauthorized, format, args...)
+}
+
+// Forbidden devuelve un error de categoría ErrForbidden.
+func Forbidden(format string, args ...interface{}) error {
+	return newError(ErrForbidden, format, args...)
+}
+
+// OverlapError indica que un turno se superpone con otro ya reservado.
+type OverlapError struct {
+	AppointmentID int
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("Appointment overlaps with appointment %d", e.AppointmentID)
+}
+func (e *OverlapError) Unwrap() error { return ErrConflict }
+
+// DuplicateError indica que otro registro ya usa un valor que debe ser único.
+type DuplicateError struct {
+	Resource   string
+	Field      string
+	Value      string
+	ExistingID int
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s %d already has %s %s", e.Resource, e.ExistingID, e.Field, e.Value)
+}
+func (e *DuplicateError) Unwrap() error { return ErrConflict }
+
+// ValidationError lista los campos inválidos de un pedido.
+type ValidationError struct {
+	Fields []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+func (e *ValidationError) Unwrap() error { return ErrValidation }
+
+// FieldError devuelve un ValidationError para un único campo.
+func FieldError(field, message string) error {
+	return &ValidationError{Fields: []models.FieldError{{Field: field, Message: message}}}
+}
@@ -0,0 +1,50 @@
+package patient
+
+import (
+	"net/http"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/security"
+	"odontology-appointments/internal/web"
+)
+
+// CheckAccess controla que un usuario limitado al dentista dentistID pueda ver el paciente,
+// es decir que el paciente tenga algún turno con ese dentista. Con dentistID 0 no hay límite.
+func (s *Service) CheckAccess(dentistID, id int) error {
+	if dentistID == 0 {
+		return nil
+	}
+	if _, err := s.store.Patients().Get(id); err != nil {
+		return notFound(err)
+	}
+	appointments, err := s.store.Appointments().List(repository.AppointmentFilter{DentistID: dentistID, PatientID: id})
+	if err != nil {
+		return err
+	}
+	if len(appointments) == 0 {
+		return domain.Forbidden("Patient %d has no appointments with dentist %d", id, dentistID)
+	}
+	return nil
+}
+
+// treated devuelve los IDs de los pacientes con algún turno con el dentista.
+func (s *Service) treated(dentistID int) (map[int]bool, error) {
+	appointments, err := s.store.Appointments().List(repository.AppointmentFilter{DentistID: dentistID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[int]bool, len(appointments))
+	for _, appointment := range appointments {
+		ids[appointment.PatientID] = true
+	}
+	return ids, nil
+}
+
+// allowed responde el error y devuelve false si el usuario del pedido no puede ver el paciente.
+func allowed(w http.ResponseWriter, r *http.Request, service *Service, id int) bool {
+	if err := service.CheckAccess(security.DentistScope(r), id); err != nil {
+		web.Fail(w, err)
+		return false
+	}
+	return true
+}
@@ -1,9 +1,13 @@
 package patient
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/security"
+	"odontology-appointments/internal/web"
 	"odontology-appointments/pkg/models"
 	"strconv"
 
@@ -12,61 +16,116 @@ import (
 
 // GET: Obtener todos los pacientes
 // @Summary Listar todos los pacientes
+// @Description Devuelve una página de pacientes. El total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link.
 // @Tags Paciente
 // @Produce json
+// @Param last_name query string false "Apellido o comienzo del apellido, sin distinguir mayúsculas"
+// @Param include_deleted query bool false "Incluir los pacientes eliminados (requiere el permiso patients:admin)"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Param sort query string false "Campos de orden separados por comas, con - para orden descendente: id, last_name, first_name, dni, registration_date"
 // @Success 200 {array} models.Patient
+// @Header 200 {integer} X-Total-Count "Total de registros sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pacientes [get]
-func GetAllPatients(db *sql.DB) http.HandlerFunc {
+func GetAllPatients(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		rows, err := db.Query("SELECT * FROM patients")
+		page, err := web.PageQuery(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
-		defer rows.Close()
+		includeDeleted, ok := security.IncludeDeleted(w, r, auth.PatientsAdmin)
+		if !ok {
+			return
+		}
+		filter := repository.PatientFilter{
+			LastName:       r.URL.Query().Get("last_name"),
+			DentistID:      security.DentistScope(r),
+			IncludeDeleted: includeDeleted,
+		}
 
-		var patients []models.Patient
-		for rows.Next() {
-			var patient models.Patient
-			rows.Scan(&patient.ID, &patient.LastName, &patient.FirstName, &patient.Address, &patient.DNI, &patient.RegistrationDate)
-			patients = append(patients, patient)
+		patients, total, err := service.List(filter, page)
+		if err != nil {
+			web.Fail(w, err)
+			return
 		}
+		web.List(w, r, page, total, patients)
+	}
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(patients)
+// GET: Buscar pacientes
+// @Summary Buscar pacientes por texto
+// @Description Busca por apellido, nombre, DNI o domicilio sin distinguir mayúsculas ni acentos. Cada palabra debe ser el comienzo de alguna palabra del paciente. Los resultados se ordenan por relevancia; el total va en X-Total-Count y los enlaces a otras páginas en Link.
+// @Tags Paciente
+// @Produce json
+// @Param q query string true "Texto a buscar, por ejemplo: perez juan"
+// @Param limit query int false "Cantidad de registros por página (1 a 500, por defecto 50)"
+// @Param offset query int false "Cantidad de registros a saltear"
+// @Success 200 {array} models.Patient
+// @Header 200 {integer} X-Total-Count "Total de resultados sin paginar"
+// @Header 200 {string} Link "Enlaces first, prev, next y last"
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /patients/search [get]
+func SearchPatients(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, err := web.PageQuery(r)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		patients, total, err := service.Search(r.URL.Query().Get("q"), security.DentistScope(r), page)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		web.List(w, r, page, total, patients)
 	}
 }
 
 // POST: Crear un nuevo paciente
 // @Summary Agregar un nuevo paciente
+// @Description Los usuarios limitados a un dentista no pueden dar de alta pacientes: el paciente
+// @Description nuevo no tiene turnos con ese dentista y no podrían verlo.
 // @Tags Paciente
 // @Accept json
 // @Produce json
 // @Param paciente body models.Patient true "Paciente"
 // @Success 201 {object} models.Patient
+// @Failure 403 {object} models.Error
+// @Failure 409 {object} models.DuplicateError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pacientes [post]
-func CreatePatient(db *sql.DB) http.HandlerFunc {
+func CreatePatient(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var patient models.Patient
-		err := json.NewDecoder(r.Body).Decode(&patient)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if security.DentistScope(r) != 0 {
+			web.Fail(w, domain.Forbidden("Users limited to a dentist cannot register patients"))
 			return
 		}
 
-		stmt, err := db.Prepare("INSERT INTO patients (last_name, first_name, address, dni, registration_date) VALUES (?, ?, ?, ?, ?)")
+		var patient models.Patient
+		err := json.NewDecoder(r.Body).Decode(&patient)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.DecodeError(w, err)
 			return
 		}
-		res, err := stmt.Exec(patient.LastName, patient.FirstName, patient.Address, patient.DNI, patient.RegistrationDate)
+
+		patient, err = service.Create(patient, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		id, _ := res.LastInsertId()
-		patient.ID = int(id)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(patient)
 	}
@@ -78,26 +137,26 @@ func CreatePatient(db *sql.DB) http.HandlerFunc {
 // @Produce json
 // @Param id path int true "ID del paciente"
 // @Success 200 {object} models.Patient
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pacientes/{id} [get]
-func GetPatientByID(db *sql.DB) http.HandlerFunc {
+func GetPatientByID(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		var patient models.Patient
-		err = db.QueryRow("SELECT * FROM patients WHERE id = ?", id).Scan(
-			&patient.ID, &patient.LastName, &patient.FirstName, &patient.Address, &patient.DNI, &patient.RegistrationDate)
+		patient, err := service.Get(id)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				http.Error(w, "Patient not found", http.StatusNotFound)
-			} else {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-			}
+			web.Fail(w, err)
 			return
 		}
 
@@ -114,33 +173,35 @@ func GetPatientByID(db *sql.DB) http.HandlerFunc {
 // @Param id path int true "ID del paciente"
 // @Param paciente body models.Patient true "Paciente"
 // @Success 200 {object} models.Patient
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.DuplicateError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pacientes/{id} [put]
-func UpdatePatient(db *sql.DB) http.HandlerFunc {
+func UpdatePatient(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
-
-		var patient models.Patient
-		err = json.NewDecoder(r.Body).Decode(&patient)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		stmt, err := db.Prepare("UPDATE patients SET last_name = ?, first_name = ?, address = ?, dni = ?, registration_date = ? WHERE id = ?")
+		var patient models.Patient
+		err = json.NewDecoder(r.Body).Decode(&patient)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.DecodeError(w, err)
 			return
 		}
 
-		_, err = stmt.Exec(patient.LastName, patient.FirstName, patient.Address, patient.DNI, patient.RegistrationDate, id)
+		patient, err = service.Update(id, patient, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
@@ -156,84 +217,31 @@ func UpdatePatient(db *sql.DB) http.HandlerFunc {
 // @Produce json
 // @Param id path int true "ID del paciente"
 // @Success 200 {object} models.Patient
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.DuplicateError
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pacientes/{id} [patch]
-func PartialUpdatePatient(db *sql.DB) http.HandlerFunc {
+func PartialUpdatePatient(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
-
-		var fields map[string]interface{}
-		err = json.NewDecoder(r.Body).Decode(&fields)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-
-		query := "UPDATE patients SET "
-		args := []interface{}{}
-		first := true
-
-		if lastName, ok := fields["last_name"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "last_name = ?"
-			args = append(args, lastName)
-			first = false
-		}
-
-		if firstName, ok := fields["first_name"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "first_name = ?"
-			args = append(args, firstName)
-			first = false
-		}
-
-		if address, ok := fields["address"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "address = ?"
-			args = append(args, address)
-			first = false
-		}
-
-		if dni, ok := fields["dni"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "dni = ?"
-			args = append(args, dni)
-			first = false
-		}
-
-		if registrationDate, ok := fields["registration_date"].(string); ok {
-			if !first {
-				query += ", "
-			}
-			query += "registration_date = ?"
-			args = append(args, registrationDate)
-		}
-
-		query += " WHERE id = ?"
-		args = append(args, id)
-
-		stmt, err := db.Prepare(query)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if !allowed(w, r, service, id) {
 			return
 		}
 
-		_, err = stmt.Exec(args...)
+		// Los campos recibidos se aplican sobre el paciente actual y se valida el resultado
+		_, err = service.Patch(id, func(patient *models.Patient) error {
+			return web.Decode(r, patient)
+		}, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
@@ -243,33 +251,66 @@ func PartialUpdatePatient(db *sql.DB) http.HandlerFunc {
 
 // DELETE: Eliminar paciente
 // @Summary Eliminar un paciente
+// @Description Es una baja lógica: el paciente deja de aparecer en los listados y puede restaurarse.
+// @Description La política indica qué hacer con sus turnos reservados o confirmados: restrict (por
+// @Description defecto) rechaza la eliminación si tiene alguno y cascade elimina todos sus turnos.
 // @Tags Paciente
 // @Produce json
 // @Param id path int true "ID del paciente"
+// @Param policy query string false "restrict o cascade"
 // @Success 204
+// @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /pacientes/{id} [delete]
-func DeletePatient(db *sql.DB) http.HandlerFunc {
+func DeletePatient(service *Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		params := mux.Vars(r)
 		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
 
-		stmt, err := db.Prepare("DELETE FROM patients WHERE id = ?")
+		if err := service.Delete(id, r.URL.Query().Get("policy"), security.Actor(r)); err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// POST: Restaurar paciente
+// @Summary Restaurar un paciente eliminado
+// @Description También restaura los turnos que se eliminaron con él, salvo los de dentistas eliminados. Falla si otro paciente activo tiene el mismo DNI.
+// @Tags Paciente
+// @Produce json
+// @Param id path int true "ID del paciente"
+// @Success 200 {object} models.Patient
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /pacientes/{id}/restore [post]
+func RestorePatient(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
 			return
 		}
 
-		_, err = stmt.Exec(id)
+		patient, err := service.Restore(id, security.Actor(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			web.Fail(w, err)
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		web.JSON(w, http.StatusOK, patient)
 	}
 }
@@ -0,0 +1,105 @@
+package patient
+
+import (
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/textsearch"
+	"sort"
+	"strings"
+)
+
+// Puntaje de cada término según el campo y cómo coincide. Un término suma solo su mejor
+// coincidencia; el DNI pesa más porque identifica al paciente.
+var searchWeights = []struct {
+	field        func(models.Patient) string
+	exact, start int
+}{
+	{func(p models.Patient) string { return p.DNI }, 100, 50},
+	{func(p models.Patient) string { return p.LastName }, 30, 20},
+	{func(p models.Patient) string { return p.FirstName }, 15, 10},
+	{func(p models.Patient) string { return p.Address }, 5, 3},
+}
+
+// Search busca pacientes por apellido, nombre, DNI o domicilio sin distinguir mayúsculas ni
+// acentos. Cada palabra de query debe ser el comienzo de alguna palabra del paciente. Los
+// resultados se ordenan por relevancia y se devuelve la página pedida con el total. Si
+// dentistID no es 0 solo se buscan los pacientes con turnos con ese dentista.
+func (s *Service) Search(query string, dentistID int, page repository.Page) ([]models.Patient, int, error) {
+	terms := textsearch.Terms(query)
+	if len(terms) == 0 {
+		return nil, 0, domain.FieldError("q", "is required")
+	}
+	if len(page.Sort) > 0 {
+		return nil, 0, domain.FieldError("sort", "is not supported, results are ordered by relevance")
+	}
+	if err := page.Check(nil); err != nil {
+		return nil, 0, err
+	}
+
+	patients, err := s.store.Patients().Search(terms)
+	if err != nil {
+		return nil, 0, err
+	}
+	if dentistID != 0 {
+		treated, err := s.treated(dentistID)
+		if err != nil {
+			return nil, 0, err
+		}
+		visible := patients[:0]
+		for _, patient := range patients {
+			if treated[patient.ID] {
+				visible = append(visible, patient)
+			}
+		}
+		patients = visible
+	}
+
+	scores := make(map[int]int, len(patients))
+	for _, patient := range patients {
+		scores[patient.ID] = score(patient, terms)
+	}
+	sort.SliceStable(patients, func(i, j int) bool {
+		a, b := patients[i], patients[j]
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.ID < b.ID
+	})
+
+	total := len(patients)
+	if page.Offset >= total {
+		return []models.Patient{}, total, nil
+	}
+	patients = patients[page.Offset:]
+	if len(patients) > page.Limit {
+		patients = patients[:page.Limit]
+	}
+	return patients, total, nil
+}
+
+// score suma, por cada término, el puntaje de su mejor coincidencia en el paciente.
+func score(patient models.Patient, terms []string) int {
+	total := 0
+	for _, term := range terms {
+		best := 0
+		for _, weight := range searchWeights {
+			for _, word := range textsearch.Terms(weight.field(patient)) {
+				points := 0
+				if word == term {
+					points = weight.exact
+				} else if strings.HasPrefix(word, term) {
+					points = weight.start
+				}
+				if points > best {
+					best = points
+				}
+			}
+		}
+		total += best
+	}
+	return total
+}
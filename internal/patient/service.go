@@ -0,0 +1,226 @@
+package patient
+
+import (
+	"odontology-appointments/internal/appointment"
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"time"
+)
+
+// Service concentra las reglas de negocio de los pacientes. Devuelve errores de dominio
+// (ver paquete domain) y no depende de HTTP. Los cambios se registran en la auditoría a
+// nombre del actor que recibe cada método.
+type Service struct {
+	store repository.Store
+}
+
+// NewService crea un servicio de pacientes sobre el almacenamiento indicado.
+func NewService(store repository.Store) *Service {
+	return &Service{store: store}
+}
+
+// List devuelve una página de los pacientes que cumplen el filtro y el total sin paginar.
+func (s *Service) List(filter repository.PatientFilter, page repository.Page) ([]models.Patient, int, error) {
+	if err := page.Check(repository.PatientSortFields); err != nil {
+		return nil, 0, err
+	}
+	return s.store.Patients().Find(filter, page)
+}
+
+// Get devuelve un paciente por ID.
+func (s *Service) Get(id int) (models.Patient, error) {
+	patient, err := s.store.Patients().Get(id)
+	return patient, notFound(err)
+}
+
+// Create valida y guarda un paciente nuevo. Si no se indica, la fecha de alta es la del día.
+func (s *Service) Create(patient models.Patient, actor string) (models.Patient, error) {
+	patient.DeletedAt = ""
+	if patient.RegistrationDate == "" {
+		patient.RegistrationDate = time.Now().Format(timeslot.DateLayout)
+	}
+	if err := validation.Check(patient); err != nil {
+		return patient, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return patient, err
+	}
+	defer tx.Rollback()
+
+	if err := checkDNI(tx, patient.DNI, 0); err != nil {
+		return patient, err
+	}
+	if err := tx.Patients().Create(&patient); err != nil {
+		return patient, err
+	}
+	if err := audit.Record(tx, actor, audit.ResourcePatient, patient.ID, audit.ActionCreate, nil, patient); err != nil {
+		return patient, err
+	}
+	return patient, tx.Commit()
+}
+
+// Update reemplaza todos los datos de un paciente.
+func (s *Service) Update(id int, patient models.Patient, actor string) (models.Patient, error) {
+	return s.patch(id, func(current *models.Patient) error {
+		*current = patient
+		return nil
+	}, audit.ActionUpdate, actor)
+}
+
+// Patch aplica apply sobre el paciente actual y guarda el resultado si es válido. Si apply
+// falla se devuelve su error sin cambios.
+func (s *Service) Patch(id int, apply func(*models.Patient) error, actor string) (models.Patient, error) {
+	return s.patch(id, apply, audit.ActionPatch, actor)
+}
+
+// patch es la parte común de Update y Patch. action es la acción que se registra en la
+// auditoría.
+func (s *Service) patch(id int, apply func(*models.Patient) error, action, actor string) (models.Patient, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Patient{}, err
+	}
+	defer tx.Rollback()
+
+	current, err := tx.Patients().Get(id)
+	if err != nil {
+		return current, notFound(err)
+	}
+	patient := current
+	if err := apply(&patient); err != nil {
+		return patient, err
+	}
+	patient.ID = id
+	patient.DeletedAt = ""
+
+	if err := validation.Check(patient); err != nil {
+		return patient, err
+	}
+	if err := checkDNI(tx, patient.DNI, id); err != nil {
+		return patient, err
+	}
+	if err := tx.Patients().Update(patient); err != nil {
+		return patient, notFound(err)
+	}
+	if err := audit.Record(tx, actor, audit.ResourcePatient, id, action, current, patient); err != nil {
+		return patient, err
+	}
+	return patient, tx.Commit()
+}
+
+// Delete da de baja un paciente. policy indica qué hacer con sus turnos reservados o
+// confirmados: appointment.PolicyRestrict (por defecto) o appointment.PolicyCascade. Los que
+// llegaron a un estado final no impiden la baja. Sus series se conservan por si se restaura.
+func (s *Service) Delete(id int, policy, actor string) error {
+	if policy == "" {
+		policy = appointment.PolicyRestrict
+	}
+	if policy != appointment.PolicyRestrict && policy != appointment.PolicyCascade {
+		return domain.Invalid("Invalid policy, expected restrict or cascade")
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	patient, err := tx.Patients().Get(id)
+	if err != nil {
+		return notFound(err)
+	}
+
+	deletedAt := time.Now().UTC().Format(time.RFC3339)
+	owned := repository.AppointmentFilter{PatientID: id}
+	if policy == appointment.PolicyCascade {
+		if err := appointment.DeleteAll(tx, owned, deletedAt, actor); err != nil {
+			return err
+		}
+	} else {
+		appointments, err := appointment.Open(tx, owned)
+		if err != nil {
+			return err
+		}
+		if len(appointments) > 0 {
+			return domain.Conflict("Patient has %d scheduled or confirmed appointments, use policy=cascade", len(appointments))
+		}
+	}
+
+	if err := tx.Patients().Delete(id, deletedAt); err != nil {
+		return err
+	}
+	deleted := patient
+	deleted.DeletedAt = deletedAt
+	if err := audit.Record(tx, actor, audit.ResourcePatient, id, audit.ActionDelete, patient, deleted); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Restore vuelve a activar un paciente eliminado junto con los turnos que se eliminaron con
+// él (policy=cascade), salvo los de dentistas que sigan eliminados.
+func (s *Service) Restore(id int, actor string) (models.Patient, error) {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.Patient{}, err
+	}
+	defer tx.Rollback()
+
+	patient, err := tx.Patients().GetDeleted(id)
+	if err != nil {
+		return patient, deletedNotFound(err)
+	}
+	if err := checkDNI(tx, patient.DNI, id); err != nil {
+		return patient, err
+	}
+	if err := tx.Patients().Restore(id); err != nil {
+		return patient, err
+	}
+	restored := patient
+	restored.DeletedAt = ""
+	if err := audit.Record(tx, actor, audit.ResourcePatient, id, audit.ActionRestore, patient, restored); err != nil {
+		return patient, err
+	}
+	owned := repository.AppointmentFilter{PatientID: id, DeletedAt: patient.DeletedAt}
+	if err := appointment.RestoreAll(tx, owned, actor); err != nil {
+		return patient, err
+	}
+	patient.DeletedAt = ""
+	return patient, tx.Commit()
+}
+
+// notFound traduce repository.ErrNotFound al error de dominio de un paciente inexistente.
+func notFound(err error) error {
+	if err == repository.ErrNotFound {
+		return domain.NotFound("Patient not found")
+	}
+	return err
+}
+
+// deletedNotFound traduce repository.ErrNotFound al error de dominio de un paciente que no
+// existe o no está eliminado.
+func deletedNotFound(err error) error {
+	if err == repository.ErrNotFound {
+		return domain.NotFound("Deleted patient not found")
+	}
+	return err
+}
+
+// checkDNI devuelve un *domain.DuplicateError si otro paciente ya tiene el mismo DNI.
+// excludeID es el registro que se está modificando, o 0 al crear.
+func checkDNI(repos repository.Repositories, dni string, excludeID int) error {
+	existing, err := repos.Patients().GetByDNI(dni)
+	if err == repository.ErrNotFound || (err == nil && existing.ID == excludeID) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &domain.DuplicateError{Resource: "Patient", Field: "dni", Value: dni, ExistingID: existing.ID}
+}
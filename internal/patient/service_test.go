@@ -0,0 +1,160 @@
+package patient
+
+import (
+	"errors"
+	"odontology-appointments/internal/appointment"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		// statuses es el estado de cada turno del paciente antes de la baja.
+		statuses []string
+		policy   string
+		want     error
+	}{
+		{"restrict without appointments", nil, "", nil},
+		{"restrict with an open appointment", []string{appointment.StatusCompleted, appointment.StatusConfirmed}, appointment.PolicyRestrict, domain.ErrConflict},
+		{"restrict with closed appointments only", []string{appointment.StatusCompleted, appointment.StatusCancelled}, appointment.PolicyRestrict, nil},
+		{"cascade", []string{appointment.StatusScheduled, appointment.StatusScheduled}, appointment.PolicyCascade, nil},
+		{"reassign is only for dentists", nil, appointment.PolicyReassign, domain.ErrInvalid},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := memory.New()
+			dentist := models.Dentist{LastName: "Dentist", FirstName: "Ana", License: "MP-1"}
+			mustDo(t, store.Dentists().Create(&dentist))
+			mustDo(t, store.Schedules().Replace(dentist.ID, []models.ScheduleBlock{{DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00"}}))
+			service := NewService(store)
+			patient, err := service.Create(models.Patient{LastName: "Patient", FirstName: "Luis", Address: "Calle 1", DNI: "30111222"}, "test")
+			mustDo(t, err)
+			appointments := appointment.NewService(store, appointment.Options{})
+			for i, status := range test.statuses {
+				booked, err := appointments.Create(models.Appointment{Date: "2030-01-07", Time: []string{"09:00", "10:00"}[i],
+					Duration: 30, PatientID: patient.ID, DentistID: dentist.ID}, "test")
+				mustDo(t, err)
+				if status != appointment.StatusScheduled {
+					_, err = appointments.ChangeStatus(booked.ID, status, "test", "test")
+					mustDo(t, err)
+				}
+			}
+
+			err = service.Delete(patient.ID, test.policy, "test")
+			if test.want == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Fatalf("error = %v, want %v", err, test.want)
+			}
+
+			_, getErr := store.Patients().Get(patient.ID)
+			if deleted := getErr == repository.ErrNotFound; deleted != (test.want == nil) {
+				t.Errorf("patient deleted = %v, want %v", deleted, test.want == nil)
+			}
+			active, err := store.Appointments().List(repository.AppointmentFilter{PatientID: patient.ID})
+			mustDo(t, err)
+			wantActive := len(test.statuses)
+			if test.policy == appointment.PolicyCascade {
+				wantActive = 0
+			}
+			if len(active) != wantActive {
+				t.Errorf("patient has %d active appointments, want %d", len(active), wantActive)
+			}
+		})
+	}
+}
+
+func TestRestoreKeepsAppointmentsOfDeletedDentists(t *testing.T) {
+	store := memory.New()
+	dentist := models.Dentist{LastName: "Dentist", FirstName: "Ana", License: "MP-1"}
+	mustDo(t, store.Dentists().Create(&dentist))
+	mustDo(t, store.Schedules().Replace(dentist.ID, []models.ScheduleBlock{{DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00"}}))
+	service := NewService(store)
+	patient, err := service.Create(models.Patient{LastName: "Patient", FirstName: "Luis", Address: "Calle 1", DNI: "30111222"}, "test")
+	mustDo(t, err)
+	booked, err := appointment.NewService(store, appointment.Options{}).Create(models.Appointment{Date: "2030-01-07", Time: "09:00",
+		Duration: 30, PatientID: patient.ID, DentistID: dentist.ID}, "test")
+	mustDo(t, err)
+
+	mustDo(t, service.Delete(patient.ID, appointment.PolicyCascade, "test"))
+	mustDo(t, store.Dentists().Delete(dentist.ID, "2030-01-01T00:00:00Z"))
+	if _, err := service.Restore(patient.ID, "test"); err != nil {
+		t.Fatalf("restore with a deleted dentist: %v", err)
+	}
+	if _, err := store.Appointments().Get(booked.ID); err != repository.ErrNotFound {
+		t.Errorf("appointment of a deleted dentist was restored: %v", err)
+	}
+}
+
+func TestDentistScope(t *testing.T) {
+	store := memory.New()
+	for _, license := range []string{"MP-1", "MP-2"} {
+		dentist := models.Dentist{LastName: "Dentist", FirstName: license, License: license}
+		mustDo(t, store.Dentists().Create(&dentist))
+		mustDo(t, store.Schedules().Replace(dentist.ID, []models.ScheduleBlock{{DayOfWeek: 1, StartTime: "09:00", EndTime: "13:00"}}))
+	}
+	service := NewService(store)
+	var patients []models.Patient
+	for _, dni := range []string{"30111222", "30111333", "30111444"} {
+		patient, err := service.Create(models.Patient{LastName: "Pérez", FirstName: dni, Address: "Calle 1", DNI: dni}, "test")
+		mustDo(t, err)
+		patients = append(patients, patient)
+	}
+	appointments := appointment.NewService(store, appointment.Options{})
+	// El paciente 1 se atiende con el dentista 1, el 2 con el dentista 2 y el 3 con ninguno
+	for i, dentistID := range []int{1, 2} {
+		_, err := appointments.Create(models.Appointment{Date: "2030-01-07", Time: "09:00", Duration: 30,
+			PatientID: patients[i].ID, DentistID: dentistID}, "test")
+		mustDo(t, err)
+	}
+
+	tests := []struct {
+		scope   int
+		visible []int
+	}{
+		{0, []int{patients[0].ID, patients[1].ID, patients[2].ID}},
+		{1, []int{patients[0].ID}},
+		{2, []int{patients[1].ID}},
+	}
+	for _, test := range tests {
+		visible := map[int]bool{}
+		for _, id := range test.visible {
+			visible[id] = true
+		}
+		for _, patient := range patients {
+			err := service.CheckAccess(test.scope, patient.ID)
+			if visible[patient.ID] && err != nil {
+				t.Errorf("dentist %d cannot access patient %d: %v", test.scope, patient.ID, err)
+			}
+			if !visible[patient.ID] && !errors.Is(err, domain.ErrForbidden) {
+				t.Errorf("dentist %d accessing patient %d: error = %v, want forbidden", test.scope, patient.ID, err)
+			}
+		}
+
+		found, total, err := service.Search("perez", test.scope, repository.Page{Limit: 10})
+		mustDo(t, err)
+		if total != len(test.visible) || len(found) != len(test.visible) {
+			t.Errorf("dentist %d finds %d patients (total %d), want %d", test.scope, len(found), total, len(test.visible))
+		}
+		for _, patient := range found {
+			if !visible[patient.ID] {
+				t.Errorf("dentist %d finds patient %d", test.scope, patient.ID)
+			}
+		}
+	}
+	if err := service.CheckAccess(1, 99); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("missing patient: error = %v, want not found", err)
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
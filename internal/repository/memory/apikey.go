@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type apiKeys struct {
+	repos
+}
+
+func (r apiKeys) List() ([]models.APIKey, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.APIKey{}
+	for _, id := range sortedIDs(d.apiKeys) {
+		list = append(list, copyKey(d.apiKeys[id]))
+	}
+	return list, nil
+}
+
+func (r apiKeys) GetByHash(hash string) (models.APIKey, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	for _, key := range d.apiKeys {
+		if key.Hash == hash && key.RevokedAt == "" {
+			return copyKey(key), nil
+		}
+	}
+	return models.APIKey{}, repository.ErrNotFound
+}
+
+func (r apiKeys) Create(key *models.APIKey) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	key.ID = d.nextID("api_keys")
+	d.apiKeys[key.ID] = copyKey(*key)
+	return nil
+}
+
+func (r apiKeys) Touch(id int, usedAt string) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	key, ok := d.apiKeys[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	key.LastUsedAt = usedAt
+	d.apiKeys[id] = key
+	return nil
+}
+
+func (r apiKeys) Revoke(id int, revokedAt string) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	key, ok := d.apiKeys[id]
+	if !ok || key.RevokedAt != "" {
+		return repository.ErrNotFound
+	}
+	key.RevokedAt = revokedAt
+	d.apiKeys[id] = key
+	return nil
+}
+
+// copyKey copia la clave junto con sus permisos.
+func copyKey(key models.APIKey) models.APIKey {
+	key.Scopes = append([]string(nil), key.Scopes...)
+	return key
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type appointments struct {
+	repos
+}
+
+func (r appointments) List(filter repository.AppointmentFilter) ([]models.Appointment, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.Appointment{}
+	for _, id := range sortedIDs(d.appointments) {
+		appointment := d.appointments[id]
+		if !matches(filter, appointment) {
+			continue
+		}
+		list = append(list, appointment)
+	}
+	return list, nil
+}
+
+func (r appointments) Find(filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+	list, err := r.List(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, total := paginate(list, page)
+	return list, total, nil
+}
+
+// matches indica si el turno cumple el filtro.
+func matches(filter repository.AppointmentFilter, appointment models.Appointment) bool {
+	deleted := appointment.DeletedAt == filter.DeletedAt
+	if filter.DeletedAt == "" {
+		deleted = filter.IncludeDeleted || appointment.DeletedAt == ""
+	}
+	return deleted && (filter.DentistID == 0 || appointment.DentistID == filter.DentistID) &&
+		(filter.PatientID == 0 || appointment.PatientID == filter.PatientID) &&
+		(filter.SeriesID == 0 || appointment.SeriesID == filter.SeriesID) &&
+		(filter.DateFrom == "" || appointment.Date >= filter.DateFrom) &&
+		(filter.DateTo == "" || appointment.Date <= filter.DateTo) &&
+		(filter.Status == "" || appointment.Status == filter.Status)
+}
+
+func (r appointments) Get(id int) (models.Appointment, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	appointment, ok := d.appointments[id]
+	if !ok || appointment.DeletedAt != "" {
+		return models.Appointment{}, repository.ErrNotFound
+	}
+	return appointment, nil
+}
+
+func (r appointments) GetDeleted(id int) (models.Appointment, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	appointment, ok := d.appointments[id]
+	if !ok || appointment.DeletedAt == "" {
+		return models.Appointment{}, repository.ErrNotFound
+	}
+	return appointment, nil
+}
+
+func (r appointments) Booked(dentistID, patientID int, from, to string, excludeID int) ([]models.Appointment, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.Appointment{}
+	for _, id := range sortedIDs(d.appointments) {
+		appointment := d.appointments[id]
+		if id == excludeID || appointment.Date < from || appointment.Date > to ||
+			appointment.Status == "cancelled" || appointment.Status == "no_show" || appointment.DeletedAt != "" ||
+			(appointment.DentistID != dentistID && appointment.PatientID != patientID) {
+			continue
+		}
+		list = append(list, appointment)
+	}
+	return list, nil
+}
+
+func (r appointments) Create(appointment *models.Appointment) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	appointment.ID = d.nextID("appointments")
+	d.appointments[appointment.ID] = *appointment
+	return nil
+}
+
+func (r appointments) Update(appointment models.Appointment) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	current, ok := d.appointments[appointment.ID]
+	if !ok || current.DeletedAt != "" {
+		return repository.ErrNotFound
+	}
+	appointment.DeletedAt = current.DeletedAt
+	d.appointments[appointment.ID] = appointment
+	return nil
+}
+
+func (r appointments) Delete(id int, deletedAt string) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	appointment, ok := d.appointments[id]
+	if !ok || appointment.DeletedAt != "" {
+		return repository.ErrNotFound
+	}
+	appointment.DeletedAt = deletedAt
+	d.appointments[id] = appointment
+	return nil
+}
+
+func (r appointments) Restore(id int) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	appointment, ok := d.appointments[id]
+	if !ok || appointment.DeletedAt == "" {
+		return repository.ErrNotFound
+	}
+	appointment.DeletedAt = ""
+	d.appointments[id] = appointment
+	return nil
+}
+
+func (r appointments) Purge(before string) (int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	purged := map[int]bool{}
+	for id, appointment := range d.appointments {
+		if appointment.DeletedAt != "" && appointment.DeletedAt < before {
+			delete(d.appointments, id)
+			purged[id] = true
+		}
+	}
+
+	changes := d.statusChanges[:0]
+	for _, change := range d.statusChanges {
+		if !purged[change.AppointmentID] {
+			changes = append(changes, change)
+		}
+	}
+	d.statusChanges = changes
+
+	reschedules := d.reschedules[:0]
+	for _, entry := range d.reschedules {
+		if !purged[entry.AppointmentID] {
+			reschedules = append(reschedules, entry)
+		}
+	}
+	d.reschedules = reschedules
+	return len(purged), nil
+}
+
+func (r appointments) AddStatusChange(change *models.StatusChange) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	change.ID = d.nextID("appointment_status_changes")
+	d.statusChanges = append(d.statusChanges, *change)
+	return nil
+}
+
+func (r appointments) StatusChanges(appointmentID int) ([]models.StatusChange, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	changes := []models.StatusChange{}
+	for _, change := range d.statusChanges {
+		if change.AppointmentID == appointmentID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (r appointments) AddReschedule(entry *models.Reschedule) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	entry.ID = d.nextID("appointment_reschedules")
+	d.reschedules = append(d.reschedules, *entry)
+	return nil
+}
+
+func (r appointments) Reschedules(appointmentID int) ([]models.Reschedule, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	history := []models.Reschedule{}
+	for _, entry := range d.reschedules {
+		if entry.AppointmentID == appointmentID {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type audit struct {
+	repos
+}
+
+func (r audit) Add(entry *models.AuditEntry) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	entry.ID = d.nextID("audit_log")
+	d.auditLog = append(d.auditLog, copyEntry(*entry))
+	return nil
+}
+
+func (r audit) Find(filter repository.AuditFilter, page repository.Page) ([]models.AuditEntry, int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.AuditEntry{}
+	for _, entry := range d.auditLog {
+		if (filter.Resource != "" && entry.Resource != filter.Resource) ||
+			(filter.ResourceID != 0 && entry.ResourceID != filter.ResourceID) ||
+			(filter.Actor != "" && entry.Actor != filter.Actor) ||
+			(filter.From != "" && entry.Timestamp < filter.From) ||
+			(filter.To != "" && entry.Timestamp > filter.To) {
+			continue
+		}
+		list = append(list, copyEntry(entry))
+	}
+	list, total := paginate(list, page)
+	return list, total, nil
+}
+
+// copyEntry copia el registro junto con sus cambios.
+func copyEntry(entry models.AuditEntry) models.AuditEntry {
+	changes := make(map[string]models.AuditChange, len(entry.Changes))
+	for field, change := range entry.Changes {
+		changes[field] = change
+	}
+	entry.Changes = changes
+	return entry
+}
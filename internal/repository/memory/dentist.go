@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type dentists struct {
+	repos
+}
+
+func (r dentists) Find(filter repository.DentistFilter, page repository.Page) ([]models.Dentist, int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.Dentist{}
+	for _, id := range sortedIDs(d.dentists) {
+		dentist := d.dentists[id]
+		if (!filter.IncludeDeleted && dentist.DeletedAt != "") ||
+			(filter.LastName != "" && !hasPrefix(dentist.LastName, filter.LastName)) {
+			continue
+		}
+		list = append(list, dentist)
+	}
+	list, total := paginate(list, page)
+	return list, total, nil
+}
+
+func (r dentists) Get(id int) (models.Dentist, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	dentist, ok := d.dentists[id]
+	if !ok || dentist.DeletedAt != "" {
+		return models.Dentist{}, repository.ErrNotFound
+	}
+	return dentist, nil
+}
+
+func (r dentists) GetDeleted(id int) (models.Dentist, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	dentist, ok := d.dentists[id]
+	if !ok || dentist.DeletedAt == "" {
+		return models.Dentist{}, repository.ErrNotFound
+	}
+	return dentist, nil
+}
+
+func (r dentists) GetByLicense(license string) (models.Dentist, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	for _, id := range sortedIDs(d.dentists) {
+		if d.dentists[id].License == license && d.dentists[id].DeletedAt == "" {
+			return d.dentists[id], nil
+		}
+	}
+	return models.Dentist{}, repository.ErrNotFound
+}
+
+func (r dentists) Create(dentist *models.Dentist) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	dentist.ID = d.nextID("dentists")
+	d.dentists[dentist.ID] = *dentist
+	return nil
+}
+
+func (r dentists) Update(dentist models.Dentist) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	current, ok := d.dentists[dentist.ID]
+	if !ok || current.DeletedAt != "" {
+		return repository.ErrNotFound
+	}
+	dentist.DeletedAt = current.DeletedAt
+	d.dentists[dentist.ID] = dentist
+	return nil
+}
+
+func (r dentists) Delete(id int, deletedAt string) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	dentist, ok := d.dentists[id]
+	if !ok || dentist.DeletedAt != "" {
+		return repository.ErrNotFound
+	}
+	dentist.DeletedAt = deletedAt
+	d.dentists[id] = dentist
+	return nil
+}
+
+func (r dentists) Restore(id int) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	dentist, ok := d.dentists[id]
+	if !ok || dentist.DeletedAt == "" {
+		return repository.ErrNotFound
+	}
+	dentist.DeletedAt = ""
+	d.dentists[id] = dentist
+	return nil
+}
+
+func (r dentists) Purge(before string) (int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	purged := 0
+	for id, dentist := range d.dentists {
+		if dentist.DeletedAt == "" || dentist.DeletedAt >= before || referenced(d, 0, id) {
+			continue
+		}
+		delete(d.schedules, id)
+		delete(d.dentists, id)
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// paginate ordena list según page y devuelve la porción pedida junto con el total de
+// registros. Los campos de orden se buscan por su nombre JSON, que coincide con la columna.
+func paginate[T any](list []T, page repository.Page) ([]T, int) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := reflect.ValueOf(list[i]), reflect.ValueOf(list[j])
+		for _, field := range page.Sort {
+			if c := compare(jsonField(a, field.Field), jsonField(b, field.Field)); c != 0 {
+				return (c < 0) != field.Desc
+			}
+		}
+		return false
+	})
+
+	total := len(list)
+	if page.Offset >= total {
+		return list[:0], total
+	}
+	list = list[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(list) {
+		list = list[:page.Limit]
+	}
+	return list, total
+}
+
+// jsonField devuelve el campo de v cuyo nombre JSON es name.
+func jsonField(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// compare compara dos campos int o string.
+func compare(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int:
+		return int(a.Int() - b.Int())
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	}
+	return 0
+}
+
+// hasPrefix indica si value empieza con prefix sin distinguir mayúsculas.
+func hasPrefix(value, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix))
+}
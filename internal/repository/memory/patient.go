@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/textsearch"
+	"strings"
+)
+
+type patients struct {
+	repos
+}
+
+func (r patients) Find(filter repository.PatientFilter, page repository.Page) ([]models.Patient, int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.Patient{}
+	for _, id := range sortedIDs(d.patients) {
+		patient := d.patients[id]
+		if (!filter.IncludeDeleted && patient.DeletedAt != "") ||
+			(filter.LastName != "" && !hasPrefix(patient.LastName, filter.LastName)) ||
+			(filter.DentistID != 0 && !treats(d, filter.DentistID, patient.ID)) {
+			continue
+		}
+		list = append(list, patient)
+	}
+	list, total := paginate(list, page)
+	return list, total, nil
+}
+
+func (r patients) Get(id int) (models.Patient, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	patient, ok := d.patients[id]
+	if !ok || patient.DeletedAt != "" {
+		return models.Patient{}, repository.ErrNotFound
+	}
+	return patient, nil
+}
+
+func (r patients) GetDeleted(id int) (models.Patient, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	patient, ok := d.patients[id]
+	if !ok || patient.DeletedAt == "" {
+		return models.Patient{}, repository.ErrNotFound
+	}
+	return patient, nil
+}
+
+func (r patients) GetByDNI(dni string) (models.Patient, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	for _, id := range sortedIDs(d.patients) {
+		if d.patients[id].DNI == dni && d.patients[id].DeletedAt == "" {
+			return d.patients[id], nil
+		}
+	}
+	return models.Patient{}, repository.ErrNotFound
+}
+
+func (r patients) Search(terms []string) ([]models.Patient, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.Patient{}
+	if len(terms) == 0 {
+		return list, nil
+	}
+	for _, id := range sortedIDs(d.patients) {
+		patient := d.patients[id]
+		if patient.DeletedAt != "" {
+			continue
+		}
+		text := strings.Join([]string{patient.LastName, patient.FirstName, patient.DNI, patient.Address}, " ")
+		found := true
+		for _, term := range terms {
+			found = found && textsearch.HasPrefix(text, term)
+		}
+		if found {
+			list = append(list, patient)
+		}
+	}
+	return list, nil
+}
+
+func (r patients) Create(patient *models.Patient) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	patient.ID = d.nextID("patients")
+	d.patients[patient.ID] = *patient
+	return nil
+}
+
+func (r patients) Update(patient models.Patient) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	current, ok := d.patients[patient.ID]
+	if !ok || current.DeletedAt != "" {
+		return repository.ErrNotFound
+	}
+	patient.DeletedAt = current.DeletedAt
+	d.patients[patient.ID] = patient
+	return nil
+}
+
+func (r patients) Delete(id int, deletedAt string) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	patient, ok := d.patients[id]
+	if !ok || patient.DeletedAt != "" {
+		return repository.ErrNotFound
+	}
+	patient.DeletedAt = deletedAt
+	d.patients[id] = patient
+	return nil
+}
+
+func (r patients) Restore(id int) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	patient, ok := d.patients[id]
+	if !ok || patient.DeletedAt == "" {
+		return repository.ErrNotFound
+	}
+	patient.DeletedAt = ""
+	d.patients[id] = patient
+	return nil
+}
+
+func (r patients) Purge(before string) (int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	purged := 0
+	for id, patient := range d.patients {
+		if patient.DeletedAt == "" || patient.DeletedAt >= before || referenced(d, id, 0) {
+			continue
+		}
+		delete(d.patients, id)
+		purged++
+	}
+	return purged, nil
+}
+
+// treats indica si el dentista tiene algún turno activo con el paciente.
+func treats(d *data, dentistID, patientID int) bool {
+	for _, appointment := range d.appointments {
+		if appointment.DentistID == dentistID && appointment.PatientID == patientID && appointment.DeletedAt == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"odontology-appointments/pkg/models"
+	"sort"
+)
+
+type schedules struct {
+	repos
+}
+
+func (r schedules) Get(dentistID int) ([]models.ScheduleBlock, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	return copyBlocks(d.schedules[dentistID]), nil
+}
+
+func (r schedules) Replace(dentistID int, blocks []models.ScheduleBlock) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	stored := copyBlocks(blocks)
+	for i := range stored {
+		stored[i].ID = d.nextID("dentist_schedules")
+		stored[i].DentistID = dentistID
+	}
+	sort.SliceStable(stored, func(i, j int) bool {
+		if stored[i].DayOfWeek != stored[j].DayOfWeek {
+			return stored[i].DayOfWeek < stored[j].DayOfWeek
+		}
+		return stored[i].StartTime < stored[j].StartTime
+	})
+	d.schedules[dentistID] = stored
+	return nil
+}
+
+func (r schedules) Clear(dentistID int) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	delete(d.schedules, dentistID)
+	return nil
+}
+
+func (r schedules) DentistIDs() ([]int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	var ids []int
+	for _, id := range sortedIDs(d.schedules) {
+		if dentist, ok := d.dentists[id]; ok && dentist.DeletedAt == "" && len(d.schedules[id]) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// copyBlocks copia las franjas y sus pausas para que quien llama no modifique lo guardado.
+func copyBlocks(blocks []models.ScheduleBlock) []models.ScheduleBlock {
+	copied := make([]models.ScheduleBlock, len(blocks))
+	for i, block := range blocks {
+		block.Breaks = append([]models.ScheduleBreak{}, block.Breaks...)
+		copied[i] = block
+	}
+	return copied
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type series struct {
+	repos
+}
+
+func (r series) List(filter repository.SeriesFilter) ([]models.AppointmentSeries, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.AppointmentSeries{}
+	for _, id := range sortedIDs(d.series) {
+		s := d.series[id]
+		if (filter.DentistID != 0 && s.DentistID != filter.DentistID) ||
+			(filter.PatientID != 0 && s.PatientID != filter.PatientID) {
+			continue
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (r series) Get(id int) (models.AppointmentSeries, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	s, ok := d.series[id]
+	if !ok {
+		return s, repository.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r series) Create(s *models.AppointmentSeries) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	s.ID = d.nextID("appointment_series")
+	stored := *s
+	stored.Appointments = nil
+	d.series[s.ID] = stored
+	return nil
+}
+
+func (r series) Update(s models.AppointmentSeries) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	if _, ok := d.series[s.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	s.Appointments = nil
+	d.series[s.ID] = s
+	return nil
+}
+
+func (r series) Delete(id int) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	if _, ok := d.series[id]; !ok {
+		return repository.ErrNotFound
+	}
+	for appointmentID, appointment := range d.appointments {
+		if appointment.SeriesID == id {
+			appointment.SeriesID = 0
+			d.appointments[appointmentID] = appointment
+		}
+	}
+	delete(d.series, id)
+	return nil
+}
+
+func (r series) Purge(before string) (int, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	purged := 0
+	for id, s := range d.series {
+		patient, dentist := d.patients[s.PatientID], d.dentists[s.DentistID]
+		expired := (patient.DeletedAt != "" && patient.DeletedAt < before) || (dentist.DeletedAt != "" && dentist.DeletedAt < before)
+		if !expired {
+			continue
+		}
+		inUse := false
+		for _, appointment := range d.appointments {
+			inUse = inUse || appointment.SeriesID == id
+		}
+		if !inUse {
+			delete(d.series, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
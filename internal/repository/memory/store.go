@@ -0,0 +1,188 @@
+// Package memory implementa los repositorios en memoria. No persiste nada y está pensado
+// para probar handlers sin un archivo de base de datos.
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"sort"
+	"sync"
+)
+
+// Store implementa repository.Store en memoria. Es seguro para uso concurrente: cada
+// operación toma un lock y una transacción lo mantiene hasta Commit o Rollback.
+type Store struct {
+	mu   sync.Mutex
+	data *data
+}
+
+// New crea un Store vacío.
+func New() *Store {
+	return &Store{data: newData()}
+}
+
+// data son las tablas. Los IDs se asignan en forma creciente por tabla, como AUTOINCREMENT.
+type data struct {
+	dentists      map[int]models.Dentist
+	patients      map[int]models.Patient
+	appointments  map[int]models.Appointment
+	series        map[int]models.AppointmentSeries
+	schedules     map[int][]models.ScheduleBlock
+	apiKeys       map[int]models.APIKey
+	users         map[int]models.UserAccount
+	statusChanges []models.StatusChange
+	reschedules   []models.Reschedule
+	auditLog      []models.AuditEntry
+	lastID        map[string]int
+}
+
+func newData() *data {
+	return &data{
+		dentists:     map[int]models.Dentist{},
+		patients:     map[int]models.Patient{},
+		appointments: map[int]models.Appointment{},
+		series:       map[int]models.AppointmentSeries{},
+		schedules:    map[int][]models.ScheduleBlock{},
+		apiKeys:      map[int]models.APIKey{},
+		users:        map[int]models.UserAccount{},
+		lastID:       map[string]int{},
+	}
+}
+
+func (d *data) nextID(table string) int {
+	d.lastID[table]++
+	return d.lastID[table]
+}
+
+// clone copia las tablas para poder descartar los cambios de una transacción. Los valores
+// se guardan siempre como copias, así que alcanza con copiar los mapas y los slices.
+func (d *data) clone() *data {
+	c := newData()
+	for id, v := range d.dentists {
+		c.dentists[id] = v
+	}
+	for id, v := range d.patients {
+		c.patients[id] = v
+	}
+	for id, v := range d.appointments {
+		c.appointments[id] = v
+	}
+	for id, v := range d.series {
+		c.series[id] = v
+	}
+	for id, v := range d.schedules {
+		c.schedules[id] = v
+	}
+	for id, v := range d.apiKeys {
+		c.apiKeys[id] = v
+	}
+	for id, v := range d.users {
+		c.users[id] = v
+	}
+	for table, id := range d.lastID {
+		c.lastID[table] = id
+	}
+	c.statusChanges = append(c.statusChanges, d.statusChanges...)
+	c.reschedules = append(c.reschedules, d.reschedules...)
+	c.auditLog = append(c.auditLog, d.auditLog...)
+	return c
+}
+
+// Begin toma el lock del Store hasta que la transacción termine.
+func (s *Store) Begin() (repository.Tx, error) {
+	s.mu.Lock()
+	return &tx{repos: repos{store: s, inTx: true}, snapshot: s.data.clone()}, nil
+}
+
+type tx struct {
+	repos
+	snapshot *data
+	done     bool
+}
+
+func (t *tx) Commit() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	t.store.mu.Unlock()
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	t.store.data = t.snapshot
+	t.store.mu.Unlock()
+	return nil
+}
+
+func (s *Store) Dentists() repository.DentistRepository { return repos{store: s}.Dentists() }
+func (s *Store) Patients() repository.PatientRepository { return repos{store: s}.Patients() }
+func (s *Store) Appointments() repository.AppointmentRepository {
+	return repos{store: s}.Appointments()
+}
+func (s *Store) Series() repository.SeriesRepository      { return repos{store: s}.Series() }
+func (s *Store) Schedules() repository.ScheduleRepository { return repos{store: s}.Schedules() }
+func (s *Store) APIKeys() repository.APIKeyRepository     { return repos{store: s}.APIKeys() }
+func (s *Store) Users() repository.UserRepository         { return repos{store: s}.Users() }
+func (s *Store) Audit() repository.AuditRepository        { return repos{store: s}.Audit() }
+
+// repos da acceso a las tablas. Fuera de una transacción cada operación toma el lock.
+type repos struct {
+	store *Store
+	inTx  bool
+}
+
+func (r repos) Dentists() repository.DentistRepository         { return dentists{r} }
+func (r repos) Patients() repository.PatientRepository         { return patients{r} }
+func (r repos) Appointments() repository.AppointmentRepository { return appointments{r} }
+func (r repos) Series() repository.SeriesRepository            { return series{r} }
+func (r repos) Schedules() repository.ScheduleRepository       { return schedules{r} }
+func (r repos) APIKeys() repository.APIKeyRepository           { return apiKeys{r} }
+func (r repos) Users() repository.UserRepository               { return users{r} }
+func (r repos) Audit() repository.AuditRepository              { return audit{r} }
+
+// lock toma el lock si hace falta y devuelve las tablas junto con la función que lo libera.
+func (r repos) lock() (*data, func()) {
+	if r.inTx {
+		return r.store.data, func() {}
+	}
+	r.store.mu.Lock()
+	return r.store.data, r.store.mu.Unlock
+}
+
+// sortedIDs devuelve las claves de un mapa ordenadas, para listar en orden de ID.
+func sortedIDs[V any](m map[int]V) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// referenced indica si algún turno o serie pertenece al paciente o al dentista indicado, o si
+// alguna cuenta de usuario está vinculada al dentista. Un ID en cero no se busca.
+func referenced(d *data, patientID, dentistID int) bool {
+	for _, appointment := range d.appointments {
+		if (patientID != 0 && appointment.PatientID == patientID) || (dentistID != 0 && appointment.DentistID == dentistID) {
+			return true
+		}
+	}
+	for _, s := range d.series {
+		if (patientID != 0 && s.PatientID == patientID) || (dentistID != 0 && s.DentistID == dentistID) {
+			return true
+		}
+	}
+	for _, user := range d.users {
+		if dentistID != 0 && user.DentistID == dentistID {
+			return true
+		}
+	}
+	return false
+}
+
+var _ repository.Store = (*Store)(nil)
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type users struct {
+	repos
+}
+
+func (r users) List() ([]models.UserAccount, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	list := []models.UserAccount{}
+	for _, id := range sortedIDs(d.users) {
+		list = append(list, d.users[id])
+	}
+	return list, nil
+}
+
+func (r users) Get(id int) (models.UserAccount, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	user, ok := d.users[id]
+	if !ok {
+		return models.UserAccount{}, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r users) GetByUsername(username string) (models.UserAccount, error) {
+	d, unlock := r.lock()
+	defer unlock()
+
+	for _, user := range d.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return models.UserAccount{}, repository.ErrNotFound
+}
+
+func (r users) Create(user *models.UserAccount) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	user.ID = d.nextID("users")
+	d.users[user.ID] = *user
+	return nil
+}
+
+func (r users) Update(user models.UserAccount) error {
+	d, unlock := r.lock()
+	defer unlock()
+
+	stored, ok := d.users[user.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.Username, user.CreatedAt = stored.Username, stored.CreatedAt
+	d.users[user.ID] = user
+	return nil
+}
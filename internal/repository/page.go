@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"fmt"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+const (
+	// DefaultLimit es la cantidad de registros por página si no se indica otra.
+	DefaultLimit = 50
+	// MaxLimit es la cantidad máxima de registros por página.
+	MaxLimit = 500
+)
+
+// Campos por los que se puede ordenar cada listado. Coinciden con los nombres JSON y con
+// las columnas de la base.
+var (
+	DentistSortFields     = []string{"id", "last_name", "first_name", "license"}
+	PatientSortFields     = []string{"id", "last_name", "first_name", "dni", "registration_date"}
+	AppointmentSortFields = []string{"id", "date", "time", "duration", "status", "patient_id", "dentist_id"}
+	AuditSortFields       = []string{"id", "timestamp", "actor", "resource", "resource_id", "action"}
+)
+
+// Page pide una porción ordenada de un listado. Los registros con el mismo valor en todos
+// los campos de Sort se ordenan por ID.
+type Page struct {
+	Limit  int
+	Offset int
+	Sort   []SortField
+}
+
+// SortField es un campo de orden.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort interpreta una lista de campos separados por comas. Un "-" delante del campo
+// invierte el orden, por ejemplo "last_name,-id".
+func ParseSort(value string) []SortField {
+	var fields []SortField
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		field := SortField{Field: strings.TrimPrefix(name, "-")}
+		field.Desc = field.Field != name
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Check valida la página contra los campos ordenables del listado. Devuelve un
+// *domain.ValidationError si algo no es válido.
+func (p Page) Check(sortable []string) error {
+	var errs []models.FieldError
+	if p.Limit < 1 || p.Limit > MaxLimit {
+		errs = append(errs, models.FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", MaxLimit)})
+	}
+	if p.Offset < 0 {
+		errs = append(errs, models.FieldError{Field: "offset", Message: "must not be negative"})
+	}
+	for _, field := range p.Sort {
+		if !contains(sortable, field.Field) {
+			errs = append(errs, models.FieldError{Field: "sort", Message: "must be one of " + strings.Join(sortable, ", ")})
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return &domain.ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
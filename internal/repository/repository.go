@@ -0,0 +1,228 @@
+// Package repository define el acceso a los datos de dentistas, pacientes y turnos. Los
+// handlers dependen de estas interfaces y no de una base de datos concreta: sqlstore las
+// implementa sobre database/sql y memory las mantiene en memoria para pruebas.
+package repository
+
+import (
+	"errors"
+	"odontology-appointments/pkg/models"
+)
+
+// ErrNotFound se devuelve cuando el registro buscado no existe.
+var ErrNotFound = errors.New("record not found")
+
+// Los dentistas, pacientes y turnos se eliminan con una baja lógica: Delete completa
+// deleted_at y a partir de ahí el registro no aparece en Get, en las búsquedas ni en los
+// listados, salvo que el filtro pida IncludeDeleted. Restore lo vuelve a activar y Purge lo
+// borra definitivamente.
+
+// DentistFilter selecciona dentistas. Los campos vacíos no filtran.
+type DentistFilter struct {
+	// LastName busca los apellidos que empiezan con el valor, sin distinguir mayúsculas.
+	LastName string
+	// IncludeDeleted incluye los dentistas eliminados.
+	IncludeDeleted bool
+}
+
+// DentistRepository guarda los dentistas.
+type DentistRepository interface {
+	// Find devuelve una página de los dentistas que cumplen el filtro y el total sin paginar.
+	Find(filter DentistFilter, page Page) ([]models.Dentist, int, error)
+	Get(id int) (models.Dentist, error)
+	GetByLicense(license string) (models.Dentist, error)
+	// GetDeleted devuelve un dentista eliminado. Si está activo devuelve ErrNotFound.
+	GetDeleted(id int) (models.Dentist, error)
+	// Create guarda el dentista y completa su ID.
+	Create(dentist *models.Dentist) error
+	Update(dentist models.Dentist) error
+	// Delete da de baja el dentista con la fecha deletedAt (RFC 3339).
+	Delete(id int, deletedAt string) error
+	Restore(id int) error
+	// Purge borra los dentistas eliminados antes de before que ya no tienen turnos ni series,
+	// junto con su agenda, y devuelve cuántos borró.
+	Purge(before string) (int, error)
+}
+
+// PatientFilter selecciona pacientes. Los campos vacíos no filtran.
+type PatientFilter struct {
+	// LastName busca los apellidos que empiezan con el valor, sin distinguir mayúsculas.
+	LastName string
+	// DentistID limita a los pacientes con algún turno activo con ese dentista.
+	DentistID int
+	// IncludeDeleted incluye los pacientes eliminados.
+	IncludeDeleted bool
+}
+
+// PatientRepository guarda los pacientes.
+type PatientRepository interface {
+	// Find devuelve una página de los pacientes que cumplen el filtro y el total sin paginar.
+	Find(filter PatientFilter, page Page) ([]models.Patient, int, error)
+	Get(id int) (models.Patient, error)
+	GetByDNI(dni string) (models.Patient, error)
+	// Search devuelve los pacientes que tienen todos los términos, cada uno como comienzo de
+	// alguna palabra del apellido, nombre, DNI o domicilio, sin distinguir mayúsculas ni
+	// acentos. Los términos deben venir normalizados con textsearch.Terms. No ordena.
+	Search(terms []string) ([]models.Patient, error)
+	// GetDeleted devuelve un paciente eliminado. Si está activo devuelve ErrNotFound.
+	GetDeleted(id int) (models.Patient, error)
+	// Create guarda el paciente y completa su ID.
+	Create(patient *models.Patient) error
+	Update(patient models.Patient) error
+	// Delete da de baja el paciente con la fecha deletedAt (RFC 3339).
+	Delete(id int, deletedAt string) error
+	Restore(id int) error
+	// Purge borra los pacientes eliminados antes de before que ya no tienen turnos ni series
+	// y devuelve cuántos borró.
+	Purge(before string) (int, error)
+}
+
+// AppointmentFilter selecciona turnos. Los campos en cero no filtran.
+type AppointmentFilter struct {
+	DentistID int
+	PatientID int
+	SeriesID  int
+	// DateFrom y DateTo limitan la fecha del turno, inclusive (YYYY-MM-DD).
+	DateFrom string
+	DateTo   string
+	Status   string
+	// IncludeDeleted incluye los turnos eliminados.
+	IncludeDeleted bool
+	// DeletedAt selecciona solo los turnos eliminados en ese momento, por ejemplo los que se
+	// eliminaron junto con su dentista.
+	DeletedAt string
+}
+
+// AppointmentRepository guarda los turnos y sus historiales de estados y reprogramaciones.
+// Los turnos se devuelven sin end_time, que se calcula a partir de time y duration.
+type AppointmentRepository interface {
+	// List devuelve los turnos que cumplen el filtro ordenados por ID.
+	List(filter AppointmentFilter) ([]models.Appointment, error)
+	// Find devuelve una página de los turnos que cumplen el filtro y el total sin paginar.
+	Find(filter AppointmentFilter, page Page) ([]models.Appointment, int, error)
+	Get(id int) (models.Appointment, error)
+	// Booked devuelve los turnos que ocupan horario (ni cancelados ni ausentes) del dentista o
+	// del paciente entre dos fechas inclusive, salvo el turno excludeID.
+	Booked(dentistID, patientID int, from, to string, excludeID int) ([]models.Appointment, error)
+	// Create guarda el turno y completa su ID. SeriesID en cero indica que no pertenece a una serie.
+	Create(appointment *models.Appointment) error
+	Update(appointment models.Appointment) error
+	// GetDeleted devuelve un turno eliminado. Si está activo devuelve ErrNotFound.
+	GetDeleted(id int) (models.Appointment, error)
+	// Delete da de baja el turno con la fecha deletedAt (RFC 3339). Sus historiales se
+	// conservan.
+	Delete(id int, deletedAt string) error
+	Restore(id int) error
+	// Purge borra los turnos eliminados antes de before junto con sus historiales y devuelve
+	// cuántos borró.
+	Purge(before string) (int, error)
+
+	// AddStatusChange registra un cambio de estado y completa su ID.
+	AddStatusChange(change *models.StatusChange) error
+	StatusChanges(appointmentID int) ([]models.StatusChange, error)
+	// AddReschedule registra una reprogramación y completa su ID.
+	AddReschedule(entry *models.Reschedule) error
+	Reschedules(appointmentID int) ([]models.Reschedule, error)
+}
+
+// SeriesFilter selecciona series de turnos. Los campos en cero no filtran.
+type SeriesFilter struct {
+	DentistID int
+	PatientID int
+}
+
+// SeriesRepository guarda las reglas de las series de turnos, sin sus turnos.
+type SeriesRepository interface {
+	List(filter SeriesFilter) ([]models.AppointmentSeries, error)
+	Get(id int) (models.AppointmentSeries, error)
+	// Create guarda la serie y completa su ID.
+	Create(series *models.AppointmentSeries) error
+	Update(series models.AppointmentSeries) error
+	// Delete elimina la serie. Sus turnos se conservan como turnos sueltos.
+	Delete(id int) error
+	// Purge borra las series sin turnos cuyo paciente o dentista se eliminó antes de before y
+	// devuelve cuántas borró.
+	Purge(before string) (int, error)
+}
+
+// ScheduleRepository guarda la agenda semanal de cada dentista.
+type ScheduleRepository interface {
+	// Get devuelve las franjas del dentista ordenadas por día y hora de inicio.
+	Get(dentistID int) ([]models.ScheduleBlock, error)
+	Replace(dentistID int, blocks []models.ScheduleBlock) error
+	Clear(dentistID int) error
+	// DentistIDs devuelve los dentistas activos que tienen agenda cargada ordenados por ID.
+	DentistIDs() ([]int, error)
+}
+
+// APIKeyRepository guarda las claves de API.
+type APIKeyRepository interface {
+	// List devuelve todas las claves, también las revocadas, ordenadas por ID.
+	List() ([]models.APIKey, error)
+	// GetByHash devuelve la clave no revocada con ese hash.
+	GetByHash(hash string) (models.APIKey, error)
+	// Create guarda la clave y completa su ID.
+	Create(key *models.APIKey) error
+	// Touch registra el último uso de la clave.
+	Touch(id int, usedAt string) error
+	// Revoke revoca la clave. Devuelve ErrNotFound si no existe o ya estaba revocada.
+	Revoke(id int, revokedAt string) error
+}
+
+// UserRepository guarda las cuentas de usuario.
+type UserRepository interface {
+	// List devuelve todas las cuentas ordenadas por ID.
+	List() ([]models.UserAccount, error)
+	Get(id int) (models.UserAccount, error)
+	// GetByUsername busca la cuenta por su nombre, que se guarda en minúsculas.
+	GetByUsername(username string) (models.UserAccount, error)
+	// Create guarda la cuenta y completa su ID.
+	Create(user *models.UserAccount) error
+	// Update guarda el hash, el rol, el dentista, los intentos fallidos y el bloqueo.
+	Update(user models.UserAccount) error
+}
+
+// AuditFilter selecciona registros de auditoría. Los campos vacíos no filtran.
+type AuditFilter struct {
+	Resource   string
+	ResourceID int
+	Actor      string
+	// From y To limitan el momento del cambio, inclusive (RFC 3339 en UTC).
+	From string
+	To   string
+}
+
+// AuditRepository guarda el registro de auditoría. Los registros no se modifican ni se borran.
+type AuditRepository interface {
+	// Add guarda el registro y completa su ID.
+	Add(entry *models.AuditEntry) error
+	// Find devuelve una página de los registros que cumplen el filtro y el total sin paginar.
+	Find(filter AuditFilter, page Page) ([]models.AuditEntry, int, error)
+}
+
+// Repositories agrupa los repositorios de un Store o de una transacción.
+type Repositories interface {
+	Dentists() DentistRepository
+	Patients() PatientRepository
+	Appointments() AppointmentRepository
+	Series() SeriesRepository
+	Schedules() ScheduleRepository
+	APIKeys() APIKeyRepository
+	Users() UserRepository
+	Audit() AuditRepository
+}
+
+// Store da acceso a los repositorios y permite agrupar operaciones en una transacción.
+type Store interface {
+	Repositories
+	// Begin inicia una transacción. Mientras está abierta ninguna otra puede escribir, lo que
+	// permite controlar superposiciones de turnos antes de guardar.
+	Begin() (Tx, error)
+}
+
+// Tx es una transacción sobre los repositorios. Rollback no hace nada después de Commit,
+// por lo que puede diferirse siempre.
+type Tx interface {
+	Repositories
+	Commit() error
+	Rollback() error
+}
@@ -0,0 +1,64 @@
+package sqlstore
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+type apiKeys struct {
+	q conn
+}
+
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, created_at, COALESCE(last_used_at, ''), COALESCE(revoked_at, '')"
+
+func scanAPIKey(row scanner) (models.APIKey, error) {
+	var key models.APIKey
+	var scopes string
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Hash, &scopes, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+	key.Scopes = strings.Fields(scopes)
+	return key, err
+}
+
+func (r apiKeys) List() ([]models.APIKey, error) {
+	rows, err := r.q.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, key)
+	}
+	return list, rows.Err()
+}
+
+func (r apiKeys) GetByHash(hash string) (models.APIKey, error) {
+	key, err := scanAPIKey(r.q.QueryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL", hash))
+	return key, notFound(err)
+}
+
+func (r apiKeys) Create(key *models.APIKey) error {
+	id, err := r.q.insert("INSERT INTO api_keys (name, prefix, key_hash, scopes, created_at) VALUES (?, ?, ?, ?, ?)",
+		key.Name, key.Prefix, key.Hash, strings.Join(key.Scopes, " "), key.CreatedAt)
+	if err != nil {
+		return err
+	}
+	key.ID = id
+	return nil
+}
+
+func (r apiKeys) Touch(id int, usedAt string) error {
+	return checkAffected(r.q.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?", usedAt, id))
+}
+
+func (r apiKeys) Revoke(id int, revokedAt string) error {
+	return checkAffected(r.q.Exec("UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", revokedAt, id))
+}
+
+var _ repository.APIKeyRepository = apiKeys{}
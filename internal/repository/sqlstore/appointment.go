@@ -0,0 +1,230 @@
+package sqlstore
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type appointments struct {
+	q conn
+}
+
+// Las columnas de texto y las referencias de las tablas originales admiten NULL, así que se
+// leen con COALESCE.
+const appointmentColumns = `id, COALESCE(date, ''), COALESCE(time, ''), duration, COALESCE(description, ''), status,
+    COALESCE(patient_id, 0), COALESCE(dentist_id, 0), COALESCE(series_id, 0), COALESCE(deleted_at, '')`
+
+func scanAppointment(row scanner) (models.Appointment, error) {
+	var appointment models.Appointment
+	err := row.Scan(&appointment.ID, &appointment.Date, &appointment.Time, &appointment.Duration, &appointment.Description,
+		&appointment.Status, &appointment.PatientID, &appointment.DentistID, &appointment.SeriesID, &appointment.DeletedAt)
+	return appointment, err
+}
+
+func (r appointments) query(query string, args ...interface{}) ([]models.Appointment, error) {
+	rows, err := r.q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Appointment{}
+	for rows.Next() {
+		appointment, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, appointment)
+	}
+	return list, rows.Err()
+}
+
+// conditions traduce el filtro a condiciones WHERE con sus argumentos.
+func (filter appointmentFilter) conditions() ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	switch {
+	case filter.DeletedAt != "":
+		conditions = append(conditions, "deleted_at = ?")
+		args = append(args, filter.DeletedAt)
+	case !filter.IncludeDeleted:
+		conditions = append(conditions, active)
+	}
+	if filter.DentistID != 0 {
+		conditions = append(conditions, "dentist_id = ?")
+		args = append(args, filter.DentistID)
+	}
+	if filter.PatientID != 0 {
+		conditions = append(conditions, "patient_id = ?")
+		args = append(args, filter.PatientID)
+	}
+	if filter.SeriesID != 0 {
+		conditions = append(conditions, "series_id = ?")
+		args = append(args, filter.SeriesID)
+	}
+	if filter.DateFrom != "" {
+		conditions = append(conditions, "date >= ?")
+		args = append(args, filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		conditions = append(conditions, "date <= ?")
+		args = append(args, filter.DateTo)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	return conditions, args
+}
+
+// appointmentFilter agrega a repository.AppointmentFilter la traducción a SQL.
+type appointmentFilter repository.AppointmentFilter
+
+func (r appointments) List(filter repository.AppointmentFilter) ([]models.Appointment, error) {
+	conditions, args := appointmentFilter(filter).conditions()
+	return r.query("SELECT "+appointmentColumns+" FROM appointments"+where(conditions)+" ORDER BY id", args...)
+}
+
+func (r appointments) Find(filter repository.AppointmentFilter, page repository.Page) ([]models.Appointment, int, error) {
+	conditions, args := appointmentFilter(filter).conditions()
+	total, err := r.q.count("SELECT COUNT(*) FROM appointments"+where(conditions), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clause, pageArgs, err := pageClause(page, repository.AppointmentSortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := r.query("SELECT "+appointmentColumns+" FROM appointments"+where(conditions)+clause, append(args, pageArgs...)...)
+	return list, total, err
+}
+
+func (r appointments) Get(id int) (models.Appointment, error) {
+	appointment, err := scanAppointment(r.q.QueryRow("SELECT "+appointmentColumns+" FROM appointments WHERE id = ? AND "+active, id))
+	return appointment, notFound(err)
+}
+
+func (r appointments) GetDeleted(id int) (models.Appointment, error) {
+	appointment, err := scanAppointment(r.q.QueryRow("SELECT "+appointmentColumns+" FROM appointments WHERE id = ? AND "+deleted, id))
+	return appointment, notFound(err)
+}
+
+func (r appointments) Booked(dentistID, patientID int, from, to string, excludeID int) ([]models.Appointment, error) {
+	return r.query(`SELECT `+appointmentColumns+` FROM appointments
+        WHERE date BETWEEN ? AND ? AND id != ? AND (dentist_id = ? OR patient_id = ?)
+        AND status NOT IN ('cancelled', 'no_show') AND `+active+` ORDER BY id`,
+		from, to, excludeID, dentistID, patientID)
+}
+
+// seriesID devuelve el valor a guardar en series_id, que queda en NULL para los turnos sueltos.
+func seriesID(appointment models.Appointment) interface{} {
+	if appointment.SeriesID == 0 {
+		return nil
+	}
+	return appointment.SeriesID
+}
+
+func (r appointments) Create(appointment *models.Appointment) error {
+	id, err := r.q.insert(`INSERT INTO appointments (date, time, duration, description, status, patient_id, dentist_id, series_id)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		appointment.Date, appointment.Time, appointment.Duration, appointment.Description, appointment.Status,
+		appointment.PatientID, appointment.DentistID, seriesID(*appointment))
+	if err != nil {
+		return err
+	}
+	appointment.ID = id
+	return nil
+}
+
+func (r appointments) Update(appointment models.Appointment) error {
+	return checkAffected(r.q.Exec(`UPDATE appointments SET date = ?, time = ?, duration = ?, description = ?, status = ?,
+        patient_id = ?, dentist_id = ?, series_id = ? WHERE id = ? AND `+active,
+		appointment.Date, appointment.Time, appointment.Duration, appointment.Description, appointment.Status,
+		appointment.PatientID, appointment.DentistID, seriesID(appointment), appointment.ID))
+}
+
+func (r appointments) Delete(id int, deletedAt string) error {
+	return checkAffected(r.q.Exec("UPDATE appointments SET deleted_at = ? WHERE id = ? AND "+active, deletedAt, id))
+}
+
+func (r appointments) Restore(id int) error {
+	return checkAffected(r.q.Exec("UPDATE appointments SET deleted_at = NULL WHERE id = ? AND "+deleted, id))
+}
+
+func (r appointments) Purge(before string) (int, error) {
+	for _, history := range []string{"appointment_status_changes", "appointment_reschedules"} {
+		_, err := r.q.Exec("DELETE FROM "+history+" WHERE appointment_id IN (SELECT id FROM appointments WHERE deleted_at < ?)", before)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return affected(r.q.Exec("DELETE FROM appointments WHERE deleted_at < ?", before))
+}
+
+func (r appointments) AddStatusChange(change *models.StatusChange) error {
+	id, err := r.q.insert(`INSERT INTO appointment_status_changes (appointment_id, from_status, to_status, reason, changed_by, changed_at)
+        VALUES (?, ?, ?, ?, ?, ?)`,
+		change.AppointmentID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedBy, change.ChangedAt)
+	if err != nil {
+		return err
+	}
+	change.ID = id
+	return nil
+}
+
+func (r appointments) StatusChanges(appointmentID int) ([]models.StatusChange, error) {
+	rows, err := r.q.Query(`SELECT id, appointment_id, from_status, to_status, reason, changed_by, changed_at
+        FROM appointment_status_changes WHERE appointment_id = ? ORDER BY id`, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.StatusChange{}
+	for rows.Next() {
+		var change models.StatusChange
+		if err := rows.Scan(&change.ID, &change.AppointmentID, &change.FromStatus, &change.ToStatus, &change.Reason, &change.ChangedBy, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r appointments) AddReschedule(entry *models.Reschedule) error {
+	id, err := r.q.insert(`INSERT INTO appointment_reschedules (appointment_id, previous_date, previous_time, previous_duration,
+        previous_dentist_id, new_date, new_time, new_duration, new_dentist_id, reason, changed_by, changed_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.AppointmentID, entry.PreviousDate, entry.PreviousTime, entry.PreviousDuration, entry.PreviousDentistID,
+		entry.NewDate, entry.NewTime, entry.NewDuration, entry.NewDentistID, entry.Reason, entry.ChangedBy, entry.ChangedAt)
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return nil
+}
+
+func (r appointments) Reschedules(appointmentID int) ([]models.Reschedule, error) {
+	rows, err := r.q.Query(`SELECT id, appointment_id, previous_date, previous_time, previous_duration, previous_dentist_id,
+        new_date, new_time, new_duration, new_dentist_id, reason, changed_by, changed_at
+        FROM appointment_reschedules WHERE appointment_id = ? ORDER BY id`, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.Reschedule{}
+	for rows.Next() {
+		var entry models.Reschedule
+		err := rows.Scan(&entry.ID, &entry.AppointmentID, &entry.PreviousDate, &entry.PreviousTime, &entry.PreviousDuration,
+			&entry.PreviousDentistID, &entry.NewDate, &entry.NewTime, &entry.NewDuration, &entry.NewDentistID,
+			&entry.Reason, &entry.ChangedBy, &entry.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+	return history, rows.Err()
+}
+
+var _ repository.AppointmentRepository = appointments{}
@@ -0,0 +1,88 @@
+package sqlstore
+
+import (
+	"encoding/json"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type audit struct {
+	q conn
+}
+
+const auditColumns = "id, actor, timestamp, resource, resource_id, action, changes"
+
+func scanAuditEntry(row scanner) (models.AuditEntry, error) {
+	var entry models.AuditEntry
+	var changes string
+	if err := row.Scan(&entry.ID, &entry.Actor, &entry.Timestamp, &entry.Resource, &entry.ResourceID, &entry.Action, &changes); err != nil {
+		return entry, err
+	}
+	err := json.Unmarshal([]byte(changes), &entry.Changes)
+	return entry, err
+}
+
+func (r audit) Add(entry *models.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+	id, err := r.q.insert("INSERT INTO audit_log (actor, timestamp, resource, resource_id, action, changes) VALUES (?, ?, ?, ?, ?, ?)",
+		entry.Actor, entry.Timestamp, entry.Resource, entry.ResourceID, entry.Action, string(changes))
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return nil
+}
+
+func (r audit) Find(filter repository.AuditFilter, page repository.Page) ([]models.AuditEntry, int, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Resource != "" {
+		conditions = append(conditions, "resource = ?")
+		args = append(args, filter.Resource)
+	}
+	if filter.ResourceID != 0 {
+		conditions = append(conditions, "resource_id = ?")
+		args = append(args, filter.ResourceID)
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+	if filter.From != "" {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, filter.To)
+	}
+
+	total, err := r.q.count("SELECT COUNT(*) FROM audit_log"+where(conditions), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clause, pageArgs, err := pageClause(page, repository.AuditSortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.q.Query("SELECT "+auditColumns+" FROM audit_log"+where(conditions)+clause, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	list := []models.AuditEntry{}
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, entry)
+	}
+	return list, total, rows.Err()
+}
+
+var _ repository.AuditRepository = audit{}
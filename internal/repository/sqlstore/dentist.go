@@ -0,0 +1,116 @@
+package sqlstore
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type dentists struct {
+	q conn
+}
+
+const dentistColumns = "id, COALESCE(last_name, ''), COALESCE(first_name, ''), COALESCE(license, ''), COALESCE(deleted_at, '')"
+
+func scanDentist(row scanner) (models.Dentist, error) {
+	var dentist models.Dentist
+	err := row.Scan(&dentist.ID, &dentist.LastName, &dentist.FirstName, &dentist.License, &dentist.DeletedAt)
+	return dentist, err
+}
+
+func (r dentists) query(query string, args ...interface{}) ([]models.Dentist, error) {
+	rows, err := r.q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Dentist{}
+	for rows.Next() {
+		dentist, err := scanDentist(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, dentist)
+	}
+	return list, rows.Err()
+}
+
+func (r dentists) Find(filter repository.DentistFilter, page repository.Page) ([]models.Dentist, int, error) {
+	var conditions []string
+	var args []interface{}
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, active)
+	}
+	if filter.LastName != "" {
+		conditions = append(conditions, `LOWER(last_name) LIKE ? ESCAPE '\'`)
+		args = append(args, likePrefix(filter.LastName))
+	}
+
+	total, err := r.q.count("SELECT COUNT(*) FROM dentists"+where(conditions), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clause, pageArgs, err := pageClause(page, repository.DentistSortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := r.query("SELECT "+dentistColumns+" FROM dentists"+where(conditions)+clause, append(args, pageArgs...)...)
+	return list, total, err
+}
+
+func (r dentists) Get(id int) (models.Dentist, error) {
+	dentist, err := scanDentist(r.q.QueryRow("SELECT "+dentistColumns+" FROM dentists WHERE id = ? AND "+active, id))
+	return dentist, notFound(err)
+}
+
+func (r dentists) GetByLicense(license string) (models.Dentist, error) {
+	dentist, err := scanDentist(r.q.QueryRow("SELECT "+dentistColumns+" FROM dentists WHERE license = ? AND "+active+" ORDER BY id LIMIT 1", license))
+	return dentist, notFound(err)
+}
+
+func (r dentists) GetDeleted(id int) (models.Dentist, error) {
+	dentist, err := scanDentist(r.q.QueryRow("SELECT "+dentistColumns+" FROM dentists WHERE id = ? AND "+deleted, id))
+	return dentist, notFound(err)
+}
+
+func (r dentists) Create(dentist *models.Dentist) error {
+	id, err := r.q.insert("INSERT INTO dentists (last_name, first_name, license) VALUES (?, ?, ?)",
+		dentist.LastName, dentist.FirstName, dentist.License)
+	if err != nil {
+		return err
+	}
+	dentist.ID = id
+	return nil
+}
+
+func (r dentists) Update(dentist models.Dentist) error {
+	return checkAffected(r.q.Exec("UPDATE dentists SET last_name = ?, first_name = ?, license = ? WHERE id = ? AND "+active,
+		dentist.LastName, dentist.FirstName, dentist.License, dentist.ID))
+}
+
+func (r dentists) Delete(id int, deletedAt string) error {
+	return checkAffected(r.q.Exec("UPDATE dentists SET deleted_at = ? WHERE id = ? AND "+active, deletedAt, id))
+}
+
+func (r dentists) Restore(id int) error {
+	return checkAffected(r.q.Exec("UPDATE dentists SET deleted_at = NULL WHERE id = ? AND "+deleted, id))
+}
+
+func (r dentists) Purge(before string) (int, error) {
+	// Los dentistas a borrar; la condición se repite en cada sentencia porque la agenda debe
+	// borrarse antes que el dentista
+	purgeable := `SELECT id FROM dentists WHERE deleted_at < ?
+        AND id NOT IN (SELECT dentist_id FROM appointments WHERE dentist_id IS NOT NULL)
+        AND id NOT IN (SELECT dentist_id FROM appointment_series)
+        AND id NOT IN (SELECT dentist_id FROM users WHERE dentist_id IS NOT NULL)`
+	if _, err := r.q.Exec(`DELETE FROM dentist_schedule_breaks WHERE schedule_id IN
+        (SELECT id FROM dentist_schedules WHERE dentist_id IN (`+purgeable+`))`, before); err != nil {
+		return 0, err
+	}
+	if _, err := r.q.Exec("DELETE FROM dentist_schedules WHERE dentist_id IN ("+purgeable+")", before); err != nil {
+		return 0, err
+	}
+	return affected(r.q.Exec("DELETE FROM dentists WHERE id IN ("+purgeable+")", before))
+}
+
+var _ repository.DentistRepository = dentists{}
@@ -0,0 +1,66 @@
+package sqlstore
+
+import (
+	"fmt"
+	"odontology-appointments/internal/repository"
+	"strings"
+)
+
+// where arma la cláusula WHERE con las condiciones recibidas, o "" si no hay ninguna.
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// pageClause arma ORDER BY, LIMIT y OFFSET para la página. Los campos de orden se
+// interpolan en la consulta, así que se controlan otra vez contra allowed aunque quien llama
+// ya haya validado la página.
+func pageClause(page repository.Page, allowed []string) (string, []interface{}, error) {
+	order := make([]string, 0, len(page.Sort)+1)
+	byID := false
+	for _, field := range page.Sort {
+		if !contains(allowed, field.Field) {
+			return "", nil, fmt.Errorf("cannot sort by %q", field.Field)
+		}
+		direction := "ASC"
+		if field.Desc {
+			direction = "DESC"
+		}
+		order = append(order, field.Field+" "+direction)
+		byID = byID || field.Field == "id"
+	}
+	if !byID {
+		order = append(order, "id ASC")
+	}
+
+	clause := " ORDER BY " + strings.Join(order, ", ")
+	if page.Limit == 0 {
+		return clause, nil, nil
+	}
+	return clause + " LIMIT ? OFFSET ?", []interface{}{page.Limit, page.Offset}, nil
+}
+
+// likePrefix devuelve el patrón LIKE que busca los valores que empiezan con prefix, sin
+// distinguir mayúsculas. Se usa con LOWER(columna) LIKE ? ESCAPE '\'.
+func likePrefix(prefix string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(prefix))
+	return escaped + "%"
+}
+
+// count devuelve el resultado de una consulta SELECT COUNT(*).
+func (c conn) count(query string, args ...interface{}) (int, error) {
+	var total int
+	err := c.QueryRow(query, args...).Scan(&total)
+	return total, err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
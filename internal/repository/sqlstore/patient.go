@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"odontology-appointments/db"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+type patients struct {
+	q conn
+}
+
+const patientColumns = `id, COALESCE(last_name, ''), COALESCE(first_name, ''), COALESCE(address, ''), COALESCE(dni, ''),
+    COALESCE(registration_date, ''), COALESCE(deleted_at, '')`
+
+func scanPatient(row scanner) (models.Patient, error) {
+	var patient models.Patient
+	err := row.Scan(&patient.ID, &patient.LastName, &patient.FirstName, &patient.Address, &patient.DNI, &patient.RegistrationDate, &patient.DeletedAt)
+	return patient, err
+}
+
+func (r patients) query(query string, args ...interface{}) ([]models.Patient, error) {
+	rows, err := r.q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Patient{}
+	for rows.Next() {
+		patient, err := scanPatient(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, patient)
+	}
+	return list, rows.Err()
+}
+
+func (r patients) Find(filter repository.PatientFilter, page repository.Page) ([]models.Patient, int, error) {
+	var conditions []string
+	var args []interface{}
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, active)
+	}
+	if filter.LastName != "" {
+		conditions = append(conditions, `LOWER(last_name) LIKE ? ESCAPE '\'`)
+		args = append(args, likePrefix(filter.LastName))
+	}
+	if filter.DentistID != 0 {
+		conditions = append(conditions, "id IN (SELECT patient_id FROM appointments WHERE dentist_id = ? AND "+active+")")
+		args = append(args, filter.DentistID)
+	}
+
+	total, err := r.q.count("SELECT COUNT(*) FROM patients"+where(conditions), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clause, pageArgs, err := pageClause(page, repository.PatientSortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := r.query("SELECT "+patientColumns+" FROM patients"+where(conditions)+clause, append(args, pageArgs...)...)
+	return list, total, err
+}
+
+func (r patients) Get(id int) (models.Patient, error) {
+	patient, err := scanPatient(r.q.QueryRow("SELECT "+patientColumns+" FROM patients WHERE id = ? AND "+active, id))
+	return patient, notFound(err)
+}
+
+func (r patients) GetByDNI(dni string) (models.Patient, error) {
+	patient, err := scanPatient(r.q.QueryRow("SELECT "+patientColumns+" FROM patients WHERE dni = ? AND "+active+" ORDER BY id LIMIT 1", dni))
+	return patient, notFound(err)
+}
+
+func (r patients) Search(terms []string) ([]models.Patient, error) {
+	if len(terms) == 0 {
+		return []models.Patient{}, nil
+	}
+	// Los términos solo tienen letras y dígitos, así que no pueden alterar la sintaxis de la
+	// búsqueda.
+	if r.q.dialect == db.Postgres {
+		return r.query("SELECT "+patientColumns+" FROM patients WHERE search_vector @@ to_tsquery('simple', ?) AND "+active,
+			strings.Join(terms, ":* & ")+":*")
+	}
+	return r.query("SELECT "+patientColumns+" FROM patients WHERE id IN (SELECT docid FROM patients_search WHERE patients_search MATCH ?) AND "+active,
+		strings.Join(terms, "* ")+"*")
+}
+
+func (r patients) GetDeleted(id int) (models.Patient, error) {
+	patient, err := scanPatient(r.q.QueryRow("SELECT "+patientColumns+" FROM patients WHERE id = ? AND "+deleted, id))
+	return patient, notFound(err)
+}
+
+func (r patients) Create(patient *models.Patient) error {
+	id, err := r.q.insert("INSERT INTO patients (last_name, first_name, address, dni, registration_date) VALUES (?, ?, ?, ?, ?)",
+		patient.LastName, patient.FirstName, patient.Address, patient.DNI, patient.RegistrationDate)
+	if err != nil {
+		return err
+	}
+	patient.ID = id
+	return nil
+}
+
+func (r patients) Update(patient models.Patient) error {
+	return checkAffected(r.q.Exec("UPDATE patients SET last_name = ?, first_name = ?, address = ?, dni = ?, registration_date = ? WHERE id = ? AND "+active,
+		patient.LastName, patient.FirstName, patient.Address, patient.DNI, patient.RegistrationDate, patient.ID))
+}
+
+func (r patients) Delete(id int, deletedAt string) error {
+	return checkAffected(r.q.Exec("UPDATE patients SET deleted_at = ? WHERE id = ? AND "+active, deletedAt, id))
+}
+
+func (r patients) Restore(id int) error {
+	return checkAffected(r.q.Exec("UPDATE patients SET deleted_at = NULL WHERE id = ? AND "+deleted, id))
+}
+
+func (r patients) Purge(before string) (int, error) {
+	return affected(r.q.Exec(`DELETE FROM patients WHERE deleted_at < ?
+        AND id NOT IN (SELECT patient_id FROM appointments WHERE patient_id IS NOT NULL)
+        AND id NOT IN (SELECT patient_id FROM appointment_series)`, before))
+}
+
+var _ repository.PatientRepository = patients{}
@@ -0,0 +1,108 @@
+package sqlstore
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type schedules struct {
+	q conn
+}
+
+func (r schedules) Get(dentistID int) ([]models.ScheduleBlock, error) {
+	rows, err := r.q.Query(`SELECT id, dentist_id, day_of_week, start_time, end_time FROM dentist_schedules
+        WHERE dentist_id = ? ORDER BY day_of_week, start_time`, dentistID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := []models.ScheduleBlock{}
+	index := map[int]int{}
+	for rows.Next() {
+		block := models.ScheduleBlock{Breaks: []models.ScheduleBreak{}}
+		if err := rows.Scan(&block.ID, &block.DentistID, &block.DayOfWeek, &block.StartTime, &block.EndTime); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index[block.ID] = len(blocks)
+		blocks = append(blocks, block)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.q.Query(`SELECT b.schedule_id, b.start_time, b.end_time FROM dentist_schedule_breaks b
+        JOIN dentist_schedules s ON s.id = b.schedule_id
+        WHERE s.dentist_id = ? ORDER BY b.start_time`, dentistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var scheduleID int
+		var pause models.ScheduleBreak
+		if err := rows.Scan(&scheduleID, &pause.StartTime, &pause.EndTime); err != nil {
+			return nil, err
+		}
+		if i, ok := index[scheduleID]; ok {
+			blocks[i].Breaks = append(blocks[i].Breaks, pause)
+		}
+	}
+
+	return blocks, rows.Err()
+}
+
+func (r schedules) Replace(dentistID int, blocks []models.ScheduleBlock) error {
+	if err := r.Clear(dentistID); err != nil {
+		return err
+	}
+
+	for _, block := range blocks {
+		scheduleID, err := r.q.insert("INSERT INTO dentist_schedules (dentist_id, day_of_week, start_time, end_time) VALUES (?, ?, ?, ?)",
+			dentistID, block.DayOfWeek, block.StartTime, block.EndTime)
+		if err != nil {
+			return err
+		}
+
+		for _, pause := range block.Breaks {
+			_, err := r.q.Exec("INSERT INTO dentist_schedule_breaks (schedule_id, start_time, end_time) VALUES (?, ?, ?)",
+				scheduleID, pause.StartTime, pause.EndTime)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r schedules) Clear(dentistID int) error {
+	_, err := r.q.Exec("DELETE FROM dentist_schedule_breaks WHERE schedule_id IN (SELECT id FROM dentist_schedules WHERE dentist_id = ?)", dentistID)
+	if err != nil {
+		return err
+	}
+	_, err = r.q.Exec("DELETE FROM dentist_schedules WHERE dentist_id = ?", dentistID)
+	return err
+}
+
+func (r schedules) DentistIDs() ([]int, error) {
+	rows, err := r.q.Query("SELECT DISTINCT dentist_id FROM dentist_schedules WHERE dentist_id IN (SELECT id FROM dentists WHERE " + active + ") ORDER BY dentist_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+var _ repository.ScheduleRepository = schedules{}
@@ -0,0 +1,93 @@
+package sqlstore
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+	"strings"
+)
+
+type series struct {
+	q conn
+}
+
+const seriesColumns = `id, patient_id, dentist_id, start_date, time, duration, COALESCE(description, ''), frequency,
+    repeat_interval, occurrence_count, until_date`
+
+func scanSeries(row scanner) (models.AppointmentSeries, error) {
+	var s models.AppointmentSeries
+	err := row.Scan(&s.ID, &s.PatientID, &s.DentistID, &s.StartDate, &s.Time, &s.Duration,
+		&s.Description, &s.Frequency, &s.Interval, &s.Count, &s.Until)
+	return s, err
+}
+
+func (r series) List(filter repository.SeriesFilter) ([]models.AppointmentSeries, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.DentistID != 0 {
+		conditions = append(conditions, "dentist_id = ?")
+		args = append(args, filter.DentistID)
+	}
+	if filter.PatientID != 0 {
+		conditions = append(conditions, "patient_id = ?")
+		args = append(args, filter.PatientID)
+	}
+
+	query := "SELECT " + seriesColumns + " FROM appointment_series"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	rows, err := r.q.Query(query+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.AppointmentSeries{}
+	for rows.Next() {
+		s, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+func (r series) Get(id int) (models.AppointmentSeries, error) {
+	s, err := scanSeries(r.q.QueryRow("SELECT "+seriesColumns+" FROM appointment_series WHERE id = ?", id))
+	return s, notFound(err)
+}
+
+func (r series) Create(s *models.AppointmentSeries) error {
+	id, err := r.q.insert(`INSERT INTO appointment_series (patient_id, dentist_id, start_date, time, duration, description,
+        frequency, repeat_interval, occurrence_count, until_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.PatientID, s.DentistID, s.StartDate, s.Time, s.Duration, s.Description,
+		s.Frequency, s.Interval, s.Count, s.Until)
+	if err != nil {
+		return err
+	}
+	s.ID = id
+	return nil
+}
+
+func (r series) Update(s models.AppointmentSeries) error {
+	return checkAffected(r.q.Exec(`UPDATE appointment_series SET patient_id = ?, dentist_id = ?, start_date = ?, time = ?,
+        duration = ?, description = ?, frequency = ?, repeat_interval = ?, occurrence_count = ?, until_date = ? WHERE id = ?`,
+		s.PatientID, s.DentistID, s.StartDate, s.Time, s.Duration, s.Description,
+		s.Frequency, s.Interval, s.Count, s.Until, s.ID))
+}
+
+func (r series) Delete(id int) error {
+	if _, err := r.q.Exec("UPDATE appointments SET series_id = NULL WHERE series_id = ?", id); err != nil {
+		return err
+	}
+	return checkAffected(r.q.Exec("DELETE FROM appointment_series WHERE id = ?", id))
+}
+
+func (r series) Purge(before string) (int, error) {
+	return affected(r.q.Exec(`DELETE FROM appointment_series
+        WHERE (patient_id IN (SELECT id FROM patients WHERE deleted_at < ?) OR dentist_id IN (SELECT id FROM dentists WHERE deleted_at < ?))
+        AND id NOT IN (SELECT series_id FROM appointments WHERE series_id IS NOT NULL)`, before, before))
+}
+
+var _ repository.SeriesRepository = series{}
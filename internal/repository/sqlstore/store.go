@@ -0,0 +1,158 @@
+// Package sqlstore implementa los repositorios sobre database/sql con SQLite o PostgreSQL.
+// Las consultas se escriben con placeholders ? y se adaptan al dialecto de la base.
+package sqlstore
+
+import (
+	"database/sql"
+	"odontology-appointments/db"
+	"odontology-appointments/internal/repository"
+)
+
+// dbtx es implementado tanto por *sql.DB como por *sql.Tx.
+type dbtx interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanner es implementado tanto por *sql.Row como por *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// writeLock es la clave del advisory lock de PostgreSQL que serializa las transacciones.
+const writeLock = 7201
+
+// Store implementa repository.Store sobre una base abierta con db.Open.
+type Store struct {
+	repos
+	database *sql.DB
+	dialect  db.Dialect
+}
+
+// New crea un Store sobre la base de datos recibida.
+func New(database *sql.DB) *Store {
+	dialect := db.DialectOf(database)
+	return &Store{repos: repos{q: conn{database, dialect}}, database: database, dialect: dialect}
+}
+
+// Begin inicia una transacción que toma el lock de escritura desde el inicio. En SQLite la
+// base se abre con _txlock=immediate; en PostgreSQL se toma un advisory lock que se libera
+// al terminar la transacción.
+func (s *Store) Begin() (repository.Tx, error) {
+	sqlTx, err := s.database.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if s.dialect == db.Postgres {
+		if _, err := sqlTx.Exec("SELECT pg_advisory_xact_lock($1)", writeLock); err != nil {
+			sqlTx.Rollback()
+			return nil, err
+		}
+	}
+	return &tx{repos: repos{q: conn{sqlTx, s.dialect}}, tx: sqlTx}, nil
+}
+
+type tx struct {
+	repos
+	tx *sql.Tx
+}
+
+func (t *tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *tx) Rollback() error {
+	if err := t.tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		return err
+	}
+	return nil
+}
+
+// conn ejecuta las consultas sobre la base o una transacción adaptando sus placeholders.
+type conn struct {
+	q       dbtx
+	dialect db.Dialect
+}
+
+func (c conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.q.Exec(c.dialect.Rebind(query), args...)
+}
+
+func (c conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.q.Query(c.dialect.Rebind(query), args...)
+}
+
+func (c conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.q.QueryRow(c.dialect.Rebind(query), args...)
+}
+
+// insert ejecuta un INSERT y devuelve el ID generado. PostgreSQL no informa LastInsertId,
+// así que allí se agrega RETURNING id.
+func (c conn) insert(query string, args ...interface{}) (int, error) {
+	if c.dialect == db.Postgres {
+		var id int
+		err := c.QueryRow(query+" RETURNING id", args...).Scan(&id)
+		return id, err
+	}
+	res, err := c.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	return int(id), err
+}
+
+// repos arma los repositorios sobre la base o sobre una transacción.
+type repos struct {
+	q conn
+}
+
+func (r repos) Dentists() repository.DentistRepository         { return dentists{r.q} }
+func (r repos) Patients() repository.PatientRepository         { return patients{r.q} }
+func (r repos) Appointments() repository.AppointmentRepository { return appointments{r.q} }
+func (r repos) Series() repository.SeriesRepository            { return series{r.q} }
+func (r repos) Schedules() repository.ScheduleRepository       { return schedules{r.q} }
+func (r repos) APIKeys() repository.APIKeyRepository           { return apiKeys{r.q} }
+func (r repos) Users() repository.UserRepository               { return users{r.q} }
+func (r repos) Audit() repository.AuditRepository              { return audit{r.q} }
+
+// notFound traduce sql.ErrNoRows al error del paquete repository.
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// Condiciones para seleccionar los registros activos o los dados de baja.
+const (
+	active  = "deleted_at IS NULL"
+	deleted = "deleted_at IS NOT NULL"
+)
+
+// affected devuelve la cantidad de filas que modificó la sentencia.
+func affected(res sql.Result, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// checkAffected devuelve repository.ErrNotFound si la sentencia no modificó ninguna fila.
+func checkAffected(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+var _ repository.Store = (*Store)(nil)
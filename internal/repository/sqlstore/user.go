@@ -0,0 +1,86 @@
+package sqlstore
+
+import (
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+type users struct {
+	q conn
+}
+
+const userColumns = `id, username, password_hash, role, COALESCE(dentist_id, 0), failed_logins,
+    COALESCE(locked_until, ''), password_changed_at, created_at`
+
+func scanUser(row scanner) (models.UserAccount, error) {
+	var user models.UserAccount
+	err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.DentistID, &user.FailedLogins,
+		&user.LockedUntil, &user.PasswordChangedAt, &user.CreatedAt)
+	return user, err
+}
+
+// userDentistID devuelve el valor a guardar en dentist_id, que queda en NULL si la cuenta no
+// está vinculada a un dentista.
+func userDentistID(user models.UserAccount) interface{} {
+	if user.DentistID == 0 {
+		return nil
+	}
+	return user.DentistID
+}
+
+// lockedUntil devuelve el valor a guardar en locked_until, que queda en NULL sin bloqueo.
+func lockedUntil(user models.UserAccount) interface{} {
+	if user.LockedUntil == "" {
+		return nil
+	}
+	return user.LockedUntil
+}
+
+func (r users) List() ([]models.UserAccount, error) {
+	rows, err := r.q.Query("SELECT " + userColumns + " FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.UserAccount{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, user)
+	}
+	return list, rows.Err()
+}
+
+func (r users) Get(id int) (models.UserAccount, error) {
+	user, err := scanUser(r.q.QueryRow("SELECT "+userColumns+" FROM users WHERE id = ?", id))
+	return user, notFound(err)
+}
+
+func (r users) GetByUsername(username string) (models.UserAccount, error) {
+	user, err := scanUser(r.q.QueryRow("SELECT "+userColumns+" FROM users WHERE username = ?", username))
+	return user, notFound(err)
+}
+
+func (r users) Create(user *models.UserAccount) error {
+	id, err := r.q.insert(`INSERT INTO users (username, password_hash, role, dentist_id, failed_logins, locked_until,
+        password_changed_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.Username, user.PasswordHash, user.Role, userDentistID(*user), user.FailedLogins, lockedUntil(*user),
+		user.PasswordChangedAt, user.CreatedAt)
+	if err != nil {
+		return err
+	}
+	user.ID = id
+	return nil
+}
+
+func (r users) Update(user models.UserAccount) error {
+	return checkAffected(r.q.Exec(`UPDATE users SET password_hash = ?, role = ?, dentist_id = ?, failed_logins = ?,
+        locked_until = ?, password_changed_at = ? WHERE id = ?`,
+		user.PasswordHash, user.Role, userDentistID(user), user.FailedLogins, lockedUntil(user),
+		user.PasswordChangedAt, user.ID))
+}
+
+var _ repository.UserRepository = users{}
@@ -0,0 +1,68 @@
+// Package retention borra definitivamente los dentistas, pacientes y turnos que fueron dados
+// de baja hace más tiempo que la retención configurada.
+package retention
+
+import (
+	"context"
+	"log"
+	"odontology-appointments/internal/repository"
+	"time"
+)
+
+// Result indica cuántos registros borró una purga.
+type Result struct {
+	Appointments int
+	Series       int
+	Patients     int
+	Dentists     int
+}
+
+// Purge borra en una transacción los registros eliminados antes de before. Los turnos se
+// borran primero; un paciente o dentista con turnos eliminados más recientemente se conserva
+// hasta que venzan también esos turnos.
+func Purge(store repository.Store, before time.Time) (Result, error) {
+	var result Result
+	cutoff := before.UTC().Format(time.RFC3339)
+
+	tx, err := store.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	if result.Appointments, err = tx.Appointments().Purge(cutoff); err != nil {
+		return result, err
+	}
+	if result.Series, err = tx.Series().Purge(cutoff); err != nil {
+		return result, err
+	}
+	if result.Patients, err = tx.Patients().Purge(cutoff); err != nil {
+		return result, err
+	}
+	if result.Dentists, err = tx.Dentists().Purge(cutoff); err != nil {
+		return result, err
+	}
+	return result, tx.Commit()
+}
+
+// Run purga cada interval los registros eliminados hace más de retention, empezando en el
+// momento de llamarla, hasta que ctx termine. Los errores se registran y no detienen el ciclo.
+func Run(ctx context.Context, store repository.Store, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		result, err := Purge(store, time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("retention: purge failed: %v", err)
+		} else if result != (Result{}) {
+			log.Printf("retention: purged %d appointments, %d series, %d patients and %d dentists",
+				result.Appointments, result.Series, result.Patients, result.Dentists)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"encoding/json"
+	"net/http"
+	"odontology-appointments/internal/web"
+	"odontology-appointments/pkg/models"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GET: Obtener la agenda semanal de un dentista
+// @Summary Obtener los horarios de atención de un dentista
+// @Tags Agenda
+// @Produce json
+// @Param id path int true "ID del dentista"
+// @Success 200 {array} models.ScheduleBlock
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /dentists/{id}/schedule [get]
+func GetSchedule(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		blocks, err := service.Get(id)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(blocks)
+	}
+}
+
+// PUT: Reemplazar la agenda semanal de un dentista
+// @Summary Reemplazar los horarios de atención de un dentista
+// @Tags Agenda
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del dentista"
+// @Param agenda body []models.ScheduleBlock true "Franjas de atención"
+// @Success 200 {array} models.ScheduleBlock
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /dentists/{id}/schedule [put]
+func UpdateSchedule(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		var blocks []models.ScheduleBlock
+		err = json.NewDecoder(r.Body).Decode(&blocks)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		saved, err := service.Replace(id, blocks)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(saved)
+	}
+}
+
+// DELETE: Eliminar la agenda semanal de un dentista
+// @Summary Eliminar los horarios de atención de un dentista
+// @Tags Agenda
+// @Param id path int true "ID del dentista"
+// @Success 204
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /dentists/{id}/schedule [delete]
+func DeleteSchedule(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		if err := service.Clear(id); err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,132 @@
+package schedule
+
+import (
+	"fmt"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+)
+
+// Validate controla que las franjas tengan días y horarios válidos, que las pausas caigan
+// dentro de su franja y que no haya superposiciones. Devuelve un *domain.ValidationError con
+// los campos inválidos, nombrados por su posición en la lista: [0].start_time,
+// [0].breaks[1].end_time.
+func Validate(blocks []models.ScheduleBlock) error {
+	type span struct{ start, end, index int }
+	byDay := map[int][]span{}
+	var errs []models.FieldError
+	add := func(field, message string) {
+		errs = append(errs, models.FieldError{Field: field, Message: message})
+	}
+
+	for i, block := range blocks {
+		prefix := fmt.Sprintf("[%d].", i)
+		if block.DayOfWeek < 0 || block.DayOfWeek > 6 {
+			add(prefix+"day_of_week", "must be between 0 (Sunday) and 6 (Saturday)")
+			continue
+		}
+		start, end, ok := checkSpan(add, prefix, block.StartTime, block.EndTime)
+		if !ok {
+			continue
+		}
+		for _, other := range byDay[block.DayOfWeek] {
+			if start < other.end && other.start < end {
+				add(prefix+"start_time", fmt.Sprintf("overlaps block [%d] on the same day", other.index))
+			}
+		}
+		byDay[block.DayOfWeek] = append(byDay[block.DayOfWeek], span{start, end, i})
+
+		var pauses []span
+		for j, pause := range block.Breaks {
+			pausePrefix := fmt.Sprintf("%sbreaks[%d].", prefix, j)
+			pauseStart, pauseEnd, ok := checkSpan(add, pausePrefix, pause.StartTime, pause.EndTime)
+			if !ok {
+				continue
+			}
+			if pauseStart < start || pauseEnd > end {
+				add(pausePrefix+"start_time", fmt.Sprintf("must be within its block %s-%s", block.StartTime, block.EndTime))
+				continue
+			}
+			for _, other := range pauses {
+				if pauseStart < other.end && other.start < pauseEnd {
+					add(pausePrefix+"start_time", fmt.Sprintf("overlaps break [%d] of the same block", other.index))
+				}
+			}
+			pauses = append(pauses, span{pauseStart, pauseEnd, j})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &domain.ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// Covers indica si el turno cae completo dentro de alguna de las franjas de atención del
+// dentista sin pisar sus pausas. Sin franjas no cubre ningún turno; qué hacer con los
+// dentistas sin horarios cargados lo decide quien reserva (ver appointment.Options).
+func Covers(blocks []models.ScheduleBlock, slot timeslot.Slot) bool {
+	start := slot.Start.Hour()*60 + slot.Start.Minute()
+	end := start + int(slot.End.Sub(slot.Start).Minutes())
+
+	for _, block := range blocks {
+		if block.DayOfWeek != int(slot.Start.Weekday()) {
+			continue
+		}
+		blockStart, blockEnd, err := parseSpan(block.StartTime, block.EndTime)
+		if err != nil || start < blockStart || end > blockEnd {
+			continue
+		}
+
+		free := true
+		for _, pause := range block.Breaks {
+			pauseStart, pauseEnd, err := parseSpan(pause.StartTime, pause.EndTime)
+			if err == nil && start < pauseEnd && pauseStart < end {
+				free = false
+				break
+			}
+		}
+		if free {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSpan controla un par de horas HH:MM de la franja o pausa cuyo prefijo de campo es
+// prefix. Agrega los errores con add y devuelve los minutos de inicio y fin, y si son válidos.
+func checkSpan(add func(field, message string), prefix, startTime, endTime string) (int, int, bool) {
+	start, startErr := timeslot.ClockMinutes(startTime)
+	if startErr != nil {
+		add(prefix+"start_time", "must be a time in HH:MM format")
+	}
+	end, endErr := timeslot.ClockMinutes(endTime)
+	if endErr != nil {
+		add(prefix+"end_time", "must be a time in HH:MM format")
+	}
+	if startErr != nil || endErr != nil {
+		return 0, 0, false
+	}
+	if start >= end {
+		add(prefix+"end_time", "must be after start_time")
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// parseSpan convierte un par de horas HH:MM en minutos y controla que el inicio sea anterior al fin.
+func parseSpan(startTime, endTime string) (int, int, error) {
+	start, err := timeslot.ClockMinutes(startTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := timeslot.ClockMinutes(endTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start >= end {
+		return 0, 0, timeslot.ErrInvalid
+	}
+	return start, end, nil
+}
@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/pkg/models"
+)
+
+// Service concentra las reglas de los horarios de atención. Devuelve errores de dominio (ver
+// paquete domain) y no depende de HTTP.
+type Service struct {
+	store repository.Store
+}
+
+// NewService crea un servicio de horarios sobre el almacenamiento indicado.
+func NewService(store repository.Store) *Service {
+	return &Service{store: store}
+}
+
+// Get devuelve las franjas de atención del dentista.
+func (s *Service) Get(dentistID int) ([]models.ScheduleBlock, error) {
+	if err := checkDentist(s.store, dentistID); err != nil {
+		return nil, err
+	}
+	return s.store.Schedules().Get(dentistID)
+}
+
+// Replace valida las franjas y reemplaza con ellas las del dentista.
+func (s *Service) Replace(dentistID int, blocks []models.ScheduleBlock) ([]models.ScheduleBlock, error) {
+	if err := Validate(blocks); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkDentist(tx, dentistID); err != nil {
+		return nil, err
+	}
+	if err := tx.Schedules().Replace(dentistID, blocks); err != nil {
+		return nil, err
+	}
+	saved, err := tx.Schedules().Get(dentistID)
+	if err != nil {
+		return nil, err
+	}
+	return saved, tx.Commit()
+}
+
+// Clear elimina todas las franjas de atención del dentista.
+func (s *Service) Clear(dentistID int) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkDentist(tx, dentistID); err != nil {
+		return err
+	}
+	if err := tx.Schedules().Clear(dentistID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkDentist devuelve un error de dominio si el dentista no existe.
+func checkDentist(repos repository.Repositories, id int) error {
+	if _, err := repos.Dentists().Get(id); err == repository.ErrNotFound {
+		return domain.NotFound("Dentist not found")
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,16 +2,125 @@ package security
 
 import (
 	"net/http"
+	"odontology-appointments/internal/apikey"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/web"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
 )
 
-// Middleware de autenticación (básico)
+// Authenticate identifica al usuario del pedido y lo guarda en su contexto. Acepta una clave
+// de API en el encabezado X-API-Key o un token en "Authorization: Bearer <token>". Los
+// pedidos sin credenciales siguen sin usuario; los que traen credenciales inválidas, vencidas
+// o revocadas se rechazan con 401.
+func Authenticate(tokens *auth.Tokens, keys *apikey.Service) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get("X-API-Key"); key != "" {
+				user, err := keys.Authenticate(key)
+				if err != nil {
+					unauthorized(w, err)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+				return
+			}
+
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, _ := strings.Cut(header, " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				unauthorized(w, domain.Unauthorized("Authorization must be a Bearer token"))
+				return
+			}
+			user, err := tokens.Parse(token)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// Middleware exige un usuario autenticado.
 func Middleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		apiKey := r.Header.Get("Authorization")
-		if apiKey != "secret-key" {
-			http.Error(w, "Forbidden", http.StatusForbidden)
+		if !Authorized(r) {
+			unauthorized(w, domain.Unauthorized("Authentication required"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Require exige un usuario autenticado cuyo rol tenga el permiso indicado.
+func Require(permission auth.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return Middleware(func(w http.ResponseWriter, r *http.Request) {
+		if !Can(r, permission) {
+			web.Fail(w, domain.Forbidden("Missing permission %s", permission))
 			return
 		}
 		next(w, r)
+	})
+}
+
+// Can indica si el usuario autenticado del pedido tiene el permiso.
+func Can(r *http.Request, permission auth.Permission) bool {
+	user, ok := auth.UserFrom(r.Context())
+	return ok && auth.Can(user, permission)
+}
+
+// DentistScope devuelve el ID del dentista al que está limitado el usuario del pedido, o 0
+// si puede ver los datos de todos los dentistas.
+func DentistScope(r *http.Request) int {
+	user, _ := auth.UserFrom(r.Context())
+	return auth.DentistScope(user)
+}
+
+// Authorized indica si el pedido trae un token válido.
+func Authorized(r *http.Request) bool {
+	_, ok := auth.UserFrom(r.Context())
+	return ok
+}
+
+// unauthorized responde 401 indicando el esquema de autenticación esperado.
+func unauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="odontology-appointments"`)
+	web.Fail(w, err)
+}
+
+// IncludeDeleted lee el parámetro include_deleted de un listado. Ver registros eliminados
+// requiere el permiso admin del recurso: si no lo tiene, o si el valor no es un booleano,
+// responde el error y devuelve ok en false.
+func IncludeDeleted(w http.ResponseWriter, r *http.Request, admin auth.Permission) (include bool, ok bool) {
+	value := r.URL.Query().Get("include_deleted")
+	if value == "" {
+		return false, true
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		web.Fail(w, domain.FieldError("include_deleted", "must be true or false"))
+		return false, false
+	}
+	if include && !Can(r, admin) {
+		web.Fail(w, domain.Forbidden("Missing permission %s to include deleted records", admin))
+		return false, false
+	}
+	return include, true
+}
+
+// Actor devuelve el nombre del usuario autenticado que realiza el pedido, o "anonymous".
+func Actor(r *http.Request) string {
+	if user, ok := auth.UserFrom(r.Context()); ok {
+		return user.Username
 	}
+	return "anonymous"
 }
@@ -0,0 +1,112 @@
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"odontology-appointments/internal/apikey"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestRouter arma un router con Authenticate y dos rutas: /records, que exige
+// appointments:read y responde el actor, el dentista al que está limitado y si incluye
+// eliminados, y /records/purge, que exige appointments:admin.
+func newTestRouter(t *testing.T) (*mux.Router, *auth.Tokens, *apikey.Service) {
+	t.Helper()
+	tokens, err := auth.NewTokens(auth.Config{Secret: []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := apikey.NewService(memory.New())
+
+	r := mux.NewRouter()
+	r.Use(Authenticate(tokens, keys))
+	r.HandleFunc("/records", Require(auth.AppointmentsRead, func(w http.ResponseWriter, r *http.Request) {
+		include, ok := IncludeDeleted(w, r, auth.AppointmentsAdmin)
+		if !ok {
+			return
+		}
+		fmt.Fprintf(w, "%s %d %v", Actor(r), DentistScope(r), include)
+	}))
+	r.HandleFunc("/records/purge", Require(auth.AppointmentsAdmin, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return r, tokens, keys
+}
+
+func TestAuthorization(t *testing.T) {
+	router, tokens, keys := newTestRouter(t)
+	bearer := func(user models.User) string {
+		pair, err := tokens.Issue(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + pair.AccessToken
+	}
+	admin := bearer(models.User{Username: "ana", Role: auth.RoleAdmin})
+	receptionist := bearer(models.User{Username: "reception", Role: auth.RoleReceptionist})
+	dentist := bearer(models.User{Username: "doctor", Role: auth.RoleDentist, DentistID: 3})
+	reader, err := keys.Create(models.APIKeyRequest{Name: "lab", Scopes: []string{string(auth.AppointmentsRead)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := keys.Create(models.APIKeyRequest{Name: "old", Scopes: []string{string(auth.AppointmentsRead)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Revoke(revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		key           string
+		status        int
+		body          string
+	}{
+		{"anonymous", "/records", "", "", http.StatusUnauthorized, ""},
+		{"not a bearer token", "/records", "Basic YWRtaW46YWRtaW4=", "", http.StatusUnauthorized, ""},
+		{"invalid token", "/records", "Bearer nope", "", http.StatusUnauthorized, ""},
+		{"admin", "/records?include_deleted=true", admin, "", http.StatusOK, "ana 0 true"},
+		{"receptionist", "/records", receptionist, "", http.StatusOK, "reception 0 false"},
+		{"receptionist including deleted", "/records?include_deleted=true", receptionist, "", http.StatusForbidden, ""},
+		{"invalid include_deleted", "/records?include_deleted=maybe", admin, "", http.StatusUnprocessableEntity, ""},
+		{"dentist is limited to its dentist", "/records", dentist, "", http.StatusOK, "doctor 3 false"},
+		{"dentist without admin permission", "/records/purge", dentist, "", http.StatusForbidden, ""},
+		{"admin with admin permission", "/records/purge", admin, "", http.StatusNoContent, ""},
+		{"API key with the scope", "/records", "", reader.Key, http.StatusOK, fmt.Sprintf("api-key:%d:lab 0 false", reader.ID)},
+		{"API key without the scope", "/records/purge", "", reader.Key, http.StatusForbidden, ""},
+		{"API key wins over the token", "/records", admin, reader.Key, http.StatusOK, fmt.Sprintf("api-key:%d:lab 0 false", reader.ID)},
+		{"revoked API key", "/records", "", revoked.Key, http.StatusUnauthorized, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", test.path, nil)
+			if test.authorization != "" {
+				request.Header.Set("Authorization", test.authorization)
+			}
+			if test.key != "" {
+				request.Header.Set("X-API-Key", test.key)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != test.status {
+				t.Fatalf("status = %d, want %d: %s", response.Code, test.status, response.Body)
+			}
+			if test.body != "" && response.Body.String() != test.body {
+				t.Errorf("body = %q, want %q", response.Body, test.body)
+			}
+			if test.status == http.StatusUnauthorized && response.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+		})
+	}
+}
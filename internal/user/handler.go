@@ -0,0 +1,147 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/web"
+	"odontology-appointments/pkg/models"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GET: Listar usuarios
+// @Summary Listar las cuentas de usuario
+// @Tags Usuarios
+// @Produce json
+// @Success 200 {array} models.UserAccount
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Security BearerAuth
+// @Router /users/ [get]
+func GetAllUsers(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		users, err := service.List()
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+		web.JSON(w, http.StatusOK, users)
+	}
+}
+
+// POST: Crear usuario
+// @Summary Crear una cuenta de usuario
+// @Description La contraseña debe tener al menos 10 caracteres y como máximo 72 bytes, letras y
+// @Description números, y no puede contener el nombre de usuario. El rol dentist requiere dentist_id.
+// @Tags Usuarios
+// @Accept json
+// @Produce json
+// @Param usuario body models.UserRequest true "Datos de la cuenta"
+// @Success 201 {object} models.UserAccount
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Router /users/ [post]
+func CreateUser(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request models.UserRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		user, err := service.Create(request)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		web.JSON(w, http.StatusCreated, user)
+	}
+}
+
+// POST: Restablecer contraseña
+// @Summary Asignar una nueva contraseña a una cuenta
+// @Description También desbloquea la cuenta. Los tokens de renovación emitidos antes dejan de servir.
+// @Tags Usuarios
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la cuenta"
+// @Param contraseña body models.PasswordReset true "Nueva contraseña"
+// @Success 200 {object} models.UserAccount
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Router /users/{id}/password [post]
+func ResetUserPassword(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		id, err := strconv.Atoi(params["id"])
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "Invalid ID")
+			return
+		}
+
+		var reset models.PasswordReset
+		if err := json.NewDecoder(r.Body).Decode(&reset); err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		user, err := service.ResetPassword(id, reset)
+		if err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		web.JSON(w, http.StatusOK, user)
+	}
+}
+
+// POST: Cambiar contraseña
+// @Summary Cambiar la contraseña propia
+// @Description Requiere la contraseña actual; si es incorrecta cuenta como intento fallido.
+// @Description Los tokens de renovación emitidos antes dejan de servir.
+// @Tags Autenticación
+// @Accept json
+// @Produce json
+// @Param contraseña body models.PasswordChange true "Contraseña actual y nueva"
+// @Success 204
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 422 {object} models.Error
+// @Security BearerAuth
+// @Router /auth/password [post]
+func ChangePassword(service *Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, _ := auth.UserFrom(r.Context())
+		if user.Role == "" {
+			web.Fail(w, domain.Forbidden("Only user accounts have a password"))
+			return
+		}
+
+		var change models.PasswordChange
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			web.DecodeError(w, err)
+			return
+		}
+
+		if err := service.ChangePassword(user.Username, change); err != nil {
+			web.Fail(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
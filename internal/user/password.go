@@ -0,0 +1,55 @@
+package user
+
+import (
+	"fmt"
+	"odontology-appointments/internal/domain"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Política de contraseñas. bcrypt solo usa los primeros 72 bytes, así que no se aceptan
+// contraseñas más largas.
+const (
+	MinPasswordLength = 10
+	maxPasswordBytes  = 72
+)
+
+// checkPassword controla que la contraseña cumpla la política. field es el campo del pedido
+// al que se refiere el error.
+func checkPassword(field, password, username string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return domain.FieldError(field, fmt.Sprintf("must be at least %d characters long", MinPasswordLength))
+	}
+	if len(password) > maxPasswordBytes {
+		return domain.FieldError(field, fmt.Sprintf("must be at most %d bytes long", maxPasswordBytes))
+	}
+	var letter, digit bool
+	for _, r := range password {
+		letter = letter || unicode.IsLetter(r)
+		digit = digit || unicode.IsDigit(r)
+	}
+	if !letter || !digit {
+		return domain.FieldError(field, "must contain at least one letter and one digit")
+	}
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return domain.FieldError(field, "must not contain the username")
+	}
+	return nil
+}
+
+// hashPassword devuelve el hash bcrypt de la contraseña.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// matches indica si la contraseña corresponde al hash.
+func matches(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyHash se compara cuando el usuario no existe, para que la respuesta tarde lo mismo que
+// con una contraseña incorrecta y no revele qué usuarios existen.
+var dummyHash, _ = hashPassword("dummy password 0")
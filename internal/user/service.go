@@ -0,0 +1,343 @@
+// Package user administra las cuentas de usuario: alta, inicio de sesión con bloqueo por
+// intentos fallidos y cambio de contraseña. Implementa auth.Credentials.
+package user
+
+import (
+	"log"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/validation"
+	"odontology-appointments/pkg/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Bloqueo por intentos fallidos: después de MaxFailedLogins contraseñas incorrectas seguidas
+// la cuenta no puede iniciar sesión durante LockoutDuration.
+const (
+	MaxFailedLogins = 5
+	LockoutDuration = 15 * time.Minute
+)
+
+// usernamePattern son los nombres de usuario válidos, ya en minúsculas.
+var usernamePattern = regexp.MustCompile(`^[a-z0-9._-]{3,50}$`)
+
+// errInvalidCredentials no distingue entre usuario inexistente y contraseña incorrecta.
+var errInvalidCredentials = domain.Unauthorized("Invalid username or password")
+
+// Service concentra las reglas de las cuentas de usuario.
+type Service struct {
+	store repository.Store
+}
+
+// NewService crea un servicio de cuentas de usuario sobre el almacenamiento indicado.
+func NewService(store repository.Store) *Service {
+	return &Service{store: store}
+}
+
+// List devuelve todas las cuentas.
+func (s *Service) List() ([]models.UserAccount, error) {
+	return s.store.Users().List()
+}
+
+// Create valida el pedido y crea la cuenta. El rol dentist debe vincularse a un dentista
+// activo; los demás roles no se vinculan.
+func (s *Service) Create(request models.UserRequest) (models.UserAccount, error) {
+	request.Username = normalize(request.Username)
+	if err := validation.Check(request); err != nil {
+		return models.UserAccount{}, err
+	}
+	if !usernamePattern.MatchString(request.Username) {
+		return models.UserAccount{}, domain.FieldError("username", "must contain 3 to 50 letters, digits, dots, dashes or underscores")
+	}
+	if err := checkPassword("password", request.Password, request.Username); err != nil {
+		return models.UserAccount{}, err
+	}
+	if err := s.checkDentist(request.Role, request.DentistID); err != nil {
+		return models.UserAccount{}, err
+	}
+	hash, err := hashPassword(request.Password)
+	if err != nil {
+		return models.UserAccount{}, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.UserAccount{}, err
+	}
+	defer tx.Rollback()
+
+	existing, err := tx.Users().GetByUsername(request.Username)
+	if err == nil {
+		return models.UserAccount{}, &domain.DuplicateError{Resource: "User", Field: "username", Value: request.Username, ExistingID: existing.ID}
+	}
+	if err != repository.ErrNotFound {
+		return models.UserAccount{}, err
+	}
+
+	now := timestamp(time.Now())
+	user := models.UserAccount{
+		Username:          request.Username,
+		Role:              request.Role,
+		DentistID:         request.DentistID,
+		PasswordChangedAt: now,
+		CreatedAt:         now,
+		PasswordHash:      hash,
+	}
+	if err := tx.Users().Create(&user); err != nil {
+		return models.UserAccount{}, err
+	}
+	return user, tx.Commit()
+}
+
+// Bootstrap crea la cuenta de administrador inicial si todavía no hay ninguna cuenta y se
+// indicó una contraseña. Devuelve si la creó.
+func (s *Service) Bootstrap(username, password string) (bool, error) {
+	users, err := s.store.Users().List()
+	if err != nil || len(users) > 0 || password == "" {
+		return false, err
+	}
+	_, err = s.Create(models.UserRequest{Username: username, Password: password, Role: auth.RoleAdmin})
+	return err == nil, err
+}
+
+// Authenticate verifica la contraseña. Cada contraseña incorrecta suma un intento fallido y al
+// llegar a MaxFailedLogins la cuenta se bloquea; un inicio de sesión correcto los reinicia.
+func (s *Service) Authenticate(username, password string) (models.User, error) {
+	user, err := s.store.Users().GetByUsername(normalize(username))
+	if err == repository.ErrNotFound {
+		matches(dummyHash, password)
+		return models.User{}, errInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	if err := s.verify(user, password); err != nil {
+		return models.User{}, err
+	}
+	return session(user), nil
+}
+
+// Current devuelve los datos vigentes de la cuenta para renovar sus tokens.
+func (s *Service) Current(username string, issuedAt time.Time) (models.User, error) {
+	user, err := s.store.Users().GetByUsername(username)
+	if err == repository.ErrNotFound {
+		return models.User{}, domain.Unauthorized("Invalid token")
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	if locked(user, time.Now()) {
+		return models.User{}, domain.Unauthorized("Account is locked, try again later")
+	}
+	changed, err := time.Parse(time.RFC3339, user.PasswordChangedAt)
+	if err != nil {
+		return models.User{}, err
+	}
+	if issuedAt.Before(changed) {
+		return models.User{}, domain.Unauthorized("The password changed after the token was issued, log in again")
+	}
+	return session(user), nil
+}
+
+// ChangePassword cambia la contraseña del usuario después de verificar la actual. Una
+// contraseña actual incorrecta cuenta como intento fallido.
+func (s *Service) ChangePassword(username string, change models.PasswordChange) error {
+	if err := validation.Check(change); err != nil {
+		return err
+	}
+
+	user, err := s.store.Users().GetByUsername(username)
+	if err != nil {
+		return notFound(err)
+	}
+	if err := s.verify(user, change.CurrentPassword); err != nil {
+		if err == errInvalidCredentials {
+			return domain.FieldError("current_password", "is incorrect")
+		}
+		return err
+	}
+	if err := checkPassword("new_password", change.NewPassword, user.Username); err != nil {
+		return err
+	}
+	if matches(user.PasswordHash, change.NewPassword) {
+		return domain.FieldError("new_password", "must be different from the current password")
+	}
+	hash, err := hashPassword(change.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := tx.Users().Get(user.ID)
+	if err != nil {
+		return notFound(err)
+	}
+	if current.PasswordHash != user.PasswordHash {
+		return domain.Conflict("The password was changed by another request, try again")
+	}
+	setPassword(&current, hash)
+	if err := tx.Users().Update(current); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ResetPassword asigna una nueva contraseña a la cuenta y la desbloquea.
+func (s *Service) ResetPassword(id int, reset models.PasswordReset) (models.UserAccount, error) {
+	if err := validation.Check(reset); err != nil {
+		return models.UserAccount{}, err
+	}
+	hash, err := hashPassword(reset.NewPassword)
+	if err != nil {
+		return models.UserAccount{}, err
+	}
+
+	tx, err := s.store.Begin()
+	if err != nil {
+		return models.UserAccount{}, err
+	}
+	defer tx.Rollback()
+
+	user, err := tx.Users().Get(id)
+	if err != nil {
+		return models.UserAccount{}, notFound(err)
+	}
+	if err := checkPassword("new_password", reset.NewPassword, user.Username); err != nil {
+		return models.UserAccount{}, err
+	}
+	setPassword(&user, hash)
+	if err := tx.Users().Update(user); err != nil {
+		return models.UserAccount{}, err
+	}
+	return user, tx.Commit()
+}
+
+// checkDentist controla el dentista vinculado según el rol.
+func (s *Service) checkDentist(role string, dentistID int) error {
+	if role != auth.RoleDentist {
+		if dentistID != 0 {
+			return domain.FieldError("dentist_id", "is only allowed for the dentist role")
+		}
+		return nil
+	}
+	if dentistID == 0 {
+		return domain.FieldError("dentist_id", "is required for the dentist role")
+	}
+	if _, err := s.store.Dentists().Get(dentistID); err == repository.ErrNotFound {
+		return domain.FieldError("dentist_id", "must be an existing dentist")
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// verify controla la contraseña de la cuenta y guarda el resultado en los intentos fallidos.
+// Una cuenta bloqueada se rechaza sin mirar la contraseña, con el mismo error que una
+// contraseña incorrecta para no revelar que el usuario existe. bcrypt es lento a propósito,
+// así que la comparación se hace fuera de toda transacción: Begin bloquea las escrituras de
+// toda la aplicación.
+func (s *Service) verify(user models.UserAccount, password string) error {
+	if locked(user, time.Now()) {
+		matches(dummyHash, password)
+		return errInvalidCredentials
+	}
+	valid := matches(user.PasswordHash, password)
+	if valid && user.FailedLogins == 0 && user.LockedUntil == "" {
+		return nil
+	}
+	return s.recordLogin(user, valid)
+}
+
+// recordLogin guarda el resultado de un intento en una transacción corta. Relee la cuenta
+// para que los intentos fallidos concurrentes se sumen y descarta el intento si mientras
+// tanto la cuenta se bloqueó o cambió de contraseña.
+func (s *Service) recordLogin(checked models.UserAccount, valid bool) error {
+	tx, err := s.store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	user, err := tx.Users().Get(checked.ID)
+	if err == repository.ErrNotFound {
+		return errInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if user.PasswordHash != checked.PasswordHash || locked(user, now) {
+		return errInvalidCredentials
+	}
+
+	if valid {
+		user.FailedLogins, user.LockedUntil = 0, ""
+	} else {
+		user.FailedLogins++
+		user.LockedUntil = ""
+		if user.FailedLogins >= MaxFailedLogins {
+			user.FailedLogins = 0
+			user.LockedUntil = timestamp(now.Add(LockoutDuration))
+		}
+	}
+	if err := tx.Users().Update(user); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if !valid {
+		return errInvalidCredentials
+	}
+	return nil
+}
+
+// locked indica si la cuenta está bloqueada en el momento now. Un bloqueo con una fecha
+// inválida se ignora, para que la cuenta no quede bloqueada para siempre.
+func locked(user models.UserAccount, now time.Time) bool {
+	if user.LockedUntil == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, user.LockedUntil)
+	if err != nil {
+		log.Printf("user %d has an invalid locked_until %q, ignoring the lock", user.ID, user.LockedUntil)
+		return false
+	}
+	return now.Before(until)
+}
+
+// setPassword guarda el hash de la nueva contraseña y desbloquea la cuenta.
+func setPassword(user *models.UserAccount, hash string) {
+	user.PasswordHash = hash
+	user.PasswordChangedAt = timestamp(time.Now())
+	user.FailedLogins, user.LockedUntil = 0, ""
+}
+
+// session devuelve los datos de la cuenta que viajan en los tokens.
+func session(user models.UserAccount) models.User {
+	return models.User{ID: user.ID, Username: user.Username, Role: user.Role, DentistID: user.DentistID}
+}
+
+func normalize(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// notFound traduce repository.ErrNotFound al error de dominio de una cuenta inexistente.
+func notFound(err error) error {
+	if err == repository.ErrNotFound {
+		return domain.NotFound("User not found")
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+package user
+
+import (
+	"errors"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository/memory"
+	"odontology-appointments/pkg/models"
+	"testing"
+	"time"
+)
+
+const password = "clinic-2030"
+
+// newTestService crea un servicio con la cuenta "reception".
+func newTestService(t *testing.T) (*Service, *memory.Store, models.UserAccount) {
+	t.Helper()
+	store := memory.New()
+	service := NewService(store)
+	account, err := service.Create(models.UserRequest{Username: " Reception ", Password: password, Role: auth.RoleReceptionist})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, store, account
+}
+
+func assertKind(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	store := memory.New()
+	dentist := models.Dentist{LastName: "Dentist", FirstName: "Ana", License: "MP-1"}
+	if err := store.Dentists().Create(&dentist); err != nil {
+		t.Fatal(err)
+	}
+	service := NewService(store)
+	if _, err := service.Create(models.UserRequest{Username: "reception", Password: password, Role: auth.RoleReceptionist}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		request models.UserRequest
+		field   string
+	}{
+		{"dentist", models.UserRequest{Username: "doctor", Password: password, Role: auth.RoleDentist, DentistID: dentist.ID}, ""},
+		{"invalid username", models.UserRequest{Username: "a b", Password: password, Role: auth.RoleAdmin}, "username"},
+		{"short password", models.UserRequest{Username: "admin", Password: "short1", Role: auth.RoleAdmin}, "password"},
+		{"password without digits", models.UserRequest{Username: "admin", Password: "only-letters", Role: auth.RoleAdmin}, "password"},
+		{"password with the username", models.UserRequest{Username: "admin", Password: "ADMIN-12345", Role: auth.RoleAdmin}, "password"},
+		{"dentist role without dentist", models.UserRequest{Username: "doctor", Password: password, Role: auth.RoleDentist}, "dentist_id"},
+		{"dentist role with a missing dentist", models.UserRequest{Username: "doctor", Password: password, Role: auth.RoleDentist, DentistID: 9}, "dentist_id"},
+		{"dentist for another role", models.UserRequest{Username: "admin", Password: password, Role: auth.RoleAdmin, DentistID: dentist.ID}, "dentist_id"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			account, err := service.Create(test.request)
+			if test.field == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if account.PasswordHash == "" || account.PasswordHash == test.request.Password {
+					t.Errorf("password hash = %q", account.PasswordHash)
+				}
+				return
+			}
+			var invalid *domain.ValidationError
+			if !errors.As(err, &invalid) || invalid.Fields[0].Field != test.field {
+				t.Fatalf("error = %v, want a validation error on %s", err, test.field)
+			}
+		})
+	}
+
+	_, err := service.Create(models.UserRequest{Username: "RECEPTION", Password: password, Role: auth.RoleAdmin})
+	var duplicate *domain.DuplicateError
+	if !errors.As(err, &duplicate) {
+		t.Errorf("error = %v, want a duplicate username", err)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	service, store, account := newTestService(t)
+
+	// Un inicio de sesión correcto reinicia los intentos fallidos
+	for i := 0; i < MaxFailedLogins-1; i++ {
+		_, err := service.Authenticate("reception", "wrong")
+		assertKind(t, err, domain.ErrUnauthorized)
+	}
+	if _, err := service.Authenticate("RECEPTION", password); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := store.Users().Get(account.ID)
+	if stored.FailedLogins != 0 {
+		t.Fatalf("failed logins = %d after a successful login, want 0", stored.FailedLogins)
+	}
+
+	for i := 0; i < MaxFailedLogins; i++ {
+		_, err := service.Authenticate("reception", "wrong")
+		if err != errInvalidCredentials {
+			t.Fatalf("attempt %d: error = %v, want the generic error", i+1, err)
+		}
+	}
+	stored, _ = store.Users().Get(account.ID)
+	until, err := time.Parse(time.RFC3339, stored.LockedUntil)
+	if err != nil || time.Until(until) < LockoutDuration-time.Minute {
+		t.Fatalf("locked until %q, want about %v from now", stored.LockedUntil, LockoutDuration)
+	}
+
+	// Bloqueada, ni la contraseña correcta entra y el error no revela el bloqueo
+	if _, err := service.Authenticate("reception", password); err != errInvalidCredentials {
+		t.Errorf("locked account: error = %v, want the generic error", err)
+	}
+	if _, err := service.Current("reception", time.Now()); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("refreshing a locked account: error = %v, want unauthorized", err)
+	}
+
+	// Vencido el bloqueo vuelve a entrar
+	stored.LockedUntil = time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+	if err := store.Users().Update(stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate("reception", password); err != nil {
+		t.Errorf("after the lockout: %v", err)
+	}
+	if stored, _ := store.Users().Get(account.ID); stored.LockedUntil != "" || stored.FailedLogins != 0 {
+		t.Errorf("account = %+v, want it unlocked", stored)
+	}
+}
+
+func TestLockoutIgnoresInvalidDates(t *testing.T) {
+	service, store, account := newTestService(t)
+	account.LockedUntil = "soon"
+	if err := store.Users().Update(account); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate("reception", password); err != nil {
+		t.Errorf("account with an invalid lock date: %v", err)
+	}
+}
+
+func TestAuthenticateUnknownUser(t *testing.T) {
+	service, _, _ := newTestService(t)
+	if _, err := service.Authenticate("nobody", password); err != errInvalidCredentials {
+		t.Errorf("error = %v, want the generic error", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	service, store, account := newTestService(t)
+	issuedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		change models.PasswordChange
+		field  string
+		failed int
+	}{
+		{"wrong current password", models.PasswordChange{CurrentPassword: "wrong", NewPassword: "another-2030"}, "current_password", 1},
+		{"same password", models.PasswordChange{CurrentPassword: password, NewPassword: password}, "new_password", 0},
+		{"weak password", models.PasswordChange{CurrentPassword: password, NewPassword: "short"}, "new_password", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := service.ChangePassword("reception", test.change)
+			var invalid *domain.ValidationError
+			if !errors.As(err, &invalid) || invalid.Fields[0].Field != test.field {
+				t.Fatalf("error = %v, want a validation error on %s", err, test.field)
+			}
+			// La contraseña actual equivocada cuenta como intento fallido y la correcta lo reinicia
+			if stored, _ := store.Users().Get(account.ID); stored.FailedLogins != test.failed {
+				t.Errorf("failed logins = %d, want %d", stored.FailedLogins, test.failed)
+			}
+		})
+	}
+
+	if err := service.ChangePassword("reception", models.PasswordChange{CurrentPassword: password, NewPassword: "another-2030"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate("reception", password); err == nil {
+		t.Error("the old password still works")
+	}
+	if _, err := service.Authenticate("reception", "another-2030"); err != nil {
+		t.Errorf("the new password does not work: %v", err)
+	}
+	// Los tokens emitidos antes del cambio ya no se renuevan
+	if _, err := service.Current("reception", issuedAt); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("refreshing an old token: error = %v, want unauthorized", err)
+	}
+	if _, err := service.Current("reception", time.Now().Add(time.Second)); err != nil {
+		t.Errorf("refreshing a new token: %v", err)
+	}
+}
+
+func TestResetPasswordUnlocks(t *testing.T) {
+	service, store, account := newTestService(t)
+	account.LockedUntil = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	account.FailedLogins = 2
+	if err := store.Users().Update(account); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.ResetPassword(account.ID, models.PasswordReset{NewPassword: "another-2030"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate("reception", "another-2030"); err != nil {
+		t.Errorf("after the reset: %v", err)
+	}
+	_, err := service.ResetPassword(99, models.PasswordReset{NewPassword: "another-2030"})
+	assertKind(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"fmt"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"odontology-appointments/pkg/timeslot"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reglas disponibles para la etiqueta `validate`. Las reglas de formato no se aplican a
+// valores vacíos; para exigir un valor se usa required.
+//
+//	required      el campo no puede estar vacío ni ser cero
+//	date          fecha ISO 8601 (YYYY-MM-DD)
+//	time          hora HH:MM de 24 horas
+//	notpast=campo la fecha junto con la hora del campo indicado no puede estar en el pasado
+//	min=N, max=N  límites para números, o largo máximo para textos (max)
+//	duration      duración en minutos entre 1 y models.MaxDuration; 0 indica que no se informó
+//	oneof=a|b     el valor debe ser uno de los indicados
+//	dni           DNI de 7 u 8 dígitos
+//	license       matrícula de 3 a 20 letras, números o guiones
+const (
+	RuleNotPast = "notpast"
+)
+
+var (
+	timePattern    = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+	datePattern    = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+	dniPattern     = regexp.MustCompile(`^[0-9]{7,8}$`)
+	licensePattern = regexp.MustCompile(`^[A-Za-z0-9-]{3,20}$`)
+)
+
+// now permite fijar la hora actual al evaluar notpast.
+var now = time.Now
+
+// Validate aplica las reglas declaradas en las etiquetas `validate` del struct y devuelve
+// todos los errores encontrados. skip permite omitir reglas puntuales, por ejemplo
+// notpast al modificar un turno sin cambiar su horario.
+func Validate(v interface{}, skip ...string) []models.FieldError {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	structType := value.Type()
+
+	skipped := map[string]bool{}
+	for _, rule := range skip {
+		skipped[rule] = true
+	}
+
+	var errs []models.FieldError
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		name := jsonName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			ruleName, param, _ := strings.Cut(rule, "=")
+			if skipped[ruleName] {
+				continue
+			}
+			if message := check(value, value.Field(i), ruleName, param); message != "" {
+				errs = append(errs, models.FieldError{Field: name, Message: message})
+				// Un error por campo alcanza; los siguientes suelen ser consecuencia del primero
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// Check es como Validate pero devuelve los errores como un *domain.ValidationError, o nil si
+// el struct es válido.
+func Check(v interface{}, skip ...string) error {
+	if errs := Validate(v, skip...); len(errs) > 0 {
+		return &domain.ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// check evalúa una regla sobre un campo y devuelve el mensaje de error, o "" si es válido.
+func check(parent, field reflect.Value, rule, param string) string {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			if rule == "required" {
+				return "is required"
+			}
+			return ""
+		}
+		field = field.Elem()
+	}
+
+	text := ""
+	if field.Kind() == reflect.String {
+		text = strings.TrimSpace(field.String())
+	}
+	if rule != "required" && field.Kind() == reflect.String && text == "" {
+		return ""
+	}
+
+	switch rule {
+	case "required":
+		if field.IsZero() || (field.Kind() == reflect.String && text == "") {
+			return "is required"
+		}
+	case "date":
+		if _, err := time.Parse(timeslot.DateLayout, text); err != nil || !datePattern.MatchString(text) {
+			return "must be a date in YYYY-MM-DD format"
+		}
+	case "time":
+		if !timePattern.MatchString(text) {
+			return "must be a time in HH:MM format"
+		}
+	case RuleNotPast:
+		clock := fieldByJSONName(parent, param)
+		start, err := timeslot.New(text, clock, 0)
+		if err == nil && start.Start.Before(now()) {
+			return "must not be in the past"
+		}
+	case "min":
+		limit, _ := strconv.Atoi(param)
+		if field.Kind() == reflect.Int && field.Int() < int64(limit) {
+			return fmt.Sprintf("must be at least %d", limit)
+		}
+	case "max":
+		limit, _ := strconv.Atoi(param)
+		if field.Kind() == reflect.Int && field.Int() > int64(limit) {
+			return fmt.Sprintf("must be at most %d", limit)
+		}
+		if field.Kind() == reflect.String && len([]rune(text)) > limit {
+			return fmt.Sprintf("must be at most %d characters long", limit)
+		}
+	case "duration":
+		if minutes := field.Int(); minutes != 0 && (minutes < 1 || minutes > models.MaxDuration) {
+			return fmt.Sprintf("must be between 1 and %d minutes", models.MaxDuration)
+		}
+	case "oneof":
+		options := strings.Split(param, "|")
+		for _, option := range options {
+			if text == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	case "dni":
+		if !dniPattern.MatchString(text) {
+			return "must contain 7 or 8 digits"
+		}
+	case "license":
+		if !licensePattern.MatchString(text) {
+			return "must contain 3 to 20 letters, digits or dashes"
+		}
+	default:
+		panic("validation: unknown rule " + rule)
+	}
+	return ""
+}
+
+// jsonName devuelve el nombre del campo en el JSON, que es el que ve el cliente.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldByJSONName busca un campo de texto del struct por su nombre en el JSON.
+func fieldByJSONName(value reflect.Value, name string) string {
+	for i := 0; i < value.NumField(); i++ {
+		if jsonName(value.Type().Field(i)) == name {
+			return strings.TrimSpace(value.Field(i).String())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"errors"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// request usa todas las reglas de la etiqueta validate.
+type request struct {
+	Date     string  `json:"date" validate:"required,date,notpast=time"`
+	Time     string  `json:"time" validate:"required,time"`
+	Duration int     `json:"duration" validate:"duration"`
+	Count    int     `json:"count" validate:"min=1,max=10"`
+	Note     *string `json:"note" validate:"max=5"`
+	Kind     string  `json:"kind,omitempty" validate:"oneof=a|b"`
+	DNI      string  `json:"dni" validate:"dni"`
+	License  string  `json:"license" validate:"license"`
+	Ignored  string
+}
+
+func valid() request {
+	return request{Date: "2030-01-07", Time: "09:00", Duration: 30, Count: 1, Kind: "a", DNI: "30111222", License: "MP-123"}
+}
+
+func TestValidate(t *testing.T) {
+	now = func() time.Time { return time.Date(2030, 1, 7, 10, 0, 0, 0, time.Local) }
+	defer func() { now = time.Now }()
+	long := "longer"
+
+	tests := []struct {
+		name   string
+		change func(*request)
+		skip   []string
+		// fields son los campos inválidos con el comienzo de su mensaje.
+		fields []string
+	}{
+		{"valid", func(r *request) { r.Time = "10:00" }, nil, nil},
+		{"empty optional fields", func(r *request) {
+			r.Time, r.Duration, r.Kind, r.DNI, r.License = "10:00", 0, "", "", ""
+		}, nil, nil},
+		{"required", func(r *request) { r.Date, r.Time = "  ", "" }, nil, []string{"date is required", "time is required"}},
+		{"formats", func(r *request) { r.Date, r.Time = "2030-02-30", "24:00" }, nil, []string{"date must be a date", "time must be a time"}},
+		{"past", func(r *request) {}, nil, []string{"date must not be in the past"}},
+		{"past skipped", func(r *request) {}, []string{RuleNotPast}, nil},
+		{"limits", func(r *request) { r.Time, r.Count, r.Note = "10:00", 11, &long }, nil, []string{"count must be at most 10", "note must be at most 5 characters"}},
+		{"minimum", func(r *request) { r.Time, r.Count = "10:00", 0 }, nil, []string{"count must be at least 1"}},
+		{"duration", func(r *request) { r.Time, r.Duration = "10:00", models.MaxDuration+1 }, nil, []string{"duration must be between 1 and 480"}},
+		{"oneof", func(r *request) { r.Time, r.Kind = "10:00", "c" }, nil, []string{"kind must be one of a, b"}},
+		{"dni and license", func(r *request) { r.Time, r.DNI, r.License = "10:00", "123", "M 1" }, nil, []string{"dni must contain", "license must contain"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := valid()
+			test.change(&r)
+
+			errs := Validate(r, test.skip...)
+			if len(errs) != len(test.fields) {
+				t.Fatalf("errors = %v, want %v", errs, test.fields)
+			}
+			for i, err := range errs {
+				if got := err.Field + " " + err.Message; !strings.HasPrefix(got, test.fields[i]) {
+					t.Errorf("error %d = %q, want %q", i, got, test.fields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(valid()); err != nil {
+		t.Errorf("Check(valid) = %v", err)
+	}
+	err := Check(&request{})
+	var invalid *domain.ValidationError
+	if !errors.As(err, &invalid) || !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("Check(empty) = %v, want a validation error", err)
+	}
+	if len(invalid.Fields) != 3 {
+		t.Errorf("fields = %v, want date, time and count", invalid.Fields)
+	}
+}
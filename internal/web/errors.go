@@ -0,0 +1,203 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/pkg/models"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Tipos de error que se devuelven en el campo "error" de models.Error.
+const (
+	TypeBadRequest    = "bad_request"
+	TypeUnauthorized  = "unauthorized"
+	TypeForbidden     = "forbidden"
+	TypeNotFound      = "not_found"
+	TypeConflict      = "conflict"
+	TypeValidation    = "validation_failed"
+	TypeUnprocessable = "unprocessable_entity"
+	TypeUnavailable   = "service_unavailable"
+	TypeInternal      = "internal_error"
+)
+
+// typeFor devuelve el tipo de error que corresponde a un código HTTP.
+func typeFor(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return TypeBadRequest
+	case http.StatusUnauthorized:
+		return TypeUnauthorized
+	case http.StatusForbidden:
+		return TypeForbidden
+	case http.StatusNotFound:
+		return TypeNotFound
+	case http.StatusConflict:
+		return TypeConflict
+	case http.StatusUnprocessableEntity:
+		return TypeUnprocessable
+	case http.StatusServiceUnavailable:
+		return TypeUnavailable
+	case http.StatusInternalServerError:
+		return TypeInternal
+	}
+	return "error"
+}
+
+// JSON escribe v como respuesta JSON con el código indicado.
+func JSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Error responde con un models.Error cuyo tipo se deduce del código HTTP.
+func Error(w http.ResponseWriter, status int, message string) {
+	JSON(w, status, models.Error{Code: status, Type: typeFor(status), Message: message})
+}
+
+// Errorf es como Error pero con un mensaje con formato.
+func Errorf(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	Error(w, status, fmt.Sprintf(format, args...))
+}
+
+// ValidationError responde 422 con el detalle de cada campo inválido.
+func ValidationError(w http.ResponseWriter, details []models.FieldError) {
+	JSON(w, http.StatusUnprocessableEntity, models.Error{
+		Code:    http.StatusUnprocessableEntity,
+		Type:    TypeValidation,
+		Message: "Validation failed",
+		Details: details,
+	})
+}
+
+// DecodeError responde 400 ante un cuerpo JSON inválido sin exponer detalles internos.
+func DecodeError(w http.ResponseWriter, err error) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		JSON(w, http.StatusBadRequest, models.Error{
+			Code:    http.StatusBadRequest,
+			Type:    TypeBadRequest,
+			Message: "Invalid request body",
+			Details: []models.FieldError{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}},
+		})
+		return
+	}
+	Error(w, http.StatusBadRequest, "Invalid request body, expected valid JSON")
+}
+
+// decodeError envuelve un error de Decode para que Fail lo responda como DecodeError.
+type decodeError struct{ err error }
+
+func (e *decodeError) Error() string { return e.err.Error() }
+func (e *decodeError) Unwrap() error { return e.err }
+
+// Decode decodifica el cuerpo JSON del pedido en v. Su error puede responderse con Fail, lo
+// que permite decodificar dentro de un servicio, por ejemplo al aplicar un PATCH.
+func Decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &decodeError{err: err}
+	}
+	return nil
+}
+
+// statusFor devuelve el código HTTP que corresponde a una categoría de error de dominio.
+func statusFor(kind error) int {
+	switch kind {
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrInvalid:
+		return http.StatusBadRequest
+	case domain.ErrValidation, domain.ErrUnprocessable:
+		return http.StatusUnprocessableEntity
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// Fail responde el error devuelto por un servicio. Los errores de dominio se traducen a su
+// código HTTP; cualquier otro error pasa por InternalError.
+func Fail(w http.ResponseWriter, err error) {
+	var (
+		decodeErr     *decodeError
+		validationErr *domain.ValidationError
+		overlapErr    *domain.OverlapError
+		duplicateErr  *domain.DuplicateError
+		domainErr     *domain.Error
+	)
+	switch {
+	case errors.As(err, &decodeErr):
+		DecodeError(w, decodeErr.err)
+	case errors.As(err, &validationErr):
+		ValidationError(w, validationErr.Fields)
+	case errors.As(err, &overlapErr):
+		JSON(w, http.StatusConflict, models.ConflictError{
+			Error:                    models.Error{Code: http.StatusConflict, Type: TypeConflict, Message: overlapErr.Error()},
+			ConflictingAppointmentID: overlapErr.AppointmentID,
+		})
+	case errors.As(err, &duplicateErr):
+		JSON(w, http.StatusConflict, models.DuplicateError{
+			Error:      models.Error{Code: http.StatusConflict, Type: TypeConflict, Message: duplicateErr.Error()},
+			Field:      duplicateErr.Field,
+			ExistingID: duplicateErr.ExistingID,
+		})
+	case errors.As(err, &domainErr):
+		Error(w, statusFor(domainErr.Kind), domainErr.Message)
+	default:
+		InternalError(w, err)
+	}
+}
+
+// Mensajes para los errores de la base de datos que el cliente puede resolver.
+const (
+	messageForeignKey = "The operation references a record that does not exist or is still in use"
+	messageUnique     = "A record with the same values already exists"
+	messageBusy       = "The database is busy, please retry"
+)
+
+// InternalError traduce un error de la base de datos o del servidor a una respuesta segura.
+// El error original solo se registra en el log.
+func InternalError(w http.ResponseWriter, err error) {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch {
+		case sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey:
+			Error(w, http.StatusUnprocessableEntity, messageForeignKey)
+			return
+		case sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey:
+			Error(w, http.StatusConflict, messageUnique)
+			return
+		case sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked:
+			Error(w, http.StatusServiceUnavailable, messageBusy)
+			return
+		}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "foreign_key_violation":
+			Error(w, http.StatusUnprocessableEntity, messageForeignKey)
+			return
+		case "unique_violation":
+			Error(w, http.StatusConflict, messageUnique)
+			return
+		case "serialization_failure", "deadlock_detected", "lock_not_available":
+			Error(w, http.StatusServiceUnavailable, messageBusy)
+			return
+		}
+	}
+
+	log.Printf("internal error: %v", err)
+	Error(w, http.StatusInternalServerError, "Internal server error")
+}
@@ -0,0 +1,82 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"strconv"
+	"strings"
+)
+
+// QueryInt lee un parámetro entero de la query. Si no está presente devuelve 0; si no es un
+// entero devuelve un error de validación sobre ese parámetro.
+func QueryInt(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, domain.FieldError(name, "must be an integer")
+	}
+	return n, nil
+}
+
+// PageQuery lee limit, offset y sort de la query. Sin limit se usa repository.DefaultLimit.
+// Los valores se validan en el servicio con repository.Page.Check.
+func PageQuery(r *http.Request) (repository.Page, error) {
+	page := repository.Page{Limit: repository.DefaultLimit}
+	var err error
+	if r.URL.Query().Get("limit") != "" {
+		if page.Limit, err = QueryInt(r, "limit"); err != nil {
+			return page, err
+		}
+	}
+	if page.Offset, err = QueryInt(r, "offset"); err != nil {
+		return page, err
+	}
+	page.Sort = repository.ParseSort(r.URL.Query().Get("sort"))
+	return page, nil
+}
+
+// List responde 200 con una página de un listado. El cuerpo es el arreglo de registros; el
+// total sin paginar va en X-Total-Count y los enlaces a otras páginas en Link (RFC 8288).
+func List(w http.ResponseWriter, r *http.Request, page repository.Page, total int, v interface{}) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if links := pageLinks(r, page, total); links != "" {
+		w.Header().Set("Link", links)
+	}
+	JSON(w, http.StatusOK, v)
+}
+
+// pageLinks arma los enlaces first, prev, next y last conservando el resto de la query.
+func pageLinks(r *http.Request, page repository.Page, total int) string {
+	if page.Limit < 1 {
+		return ""
+	}
+	link := func(offset int, rel string) string {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(page.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, query.Encode(), rel)
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / page.Limit * page.Limit
+	}
+	links := []string{link(0, "first")}
+	if page.Offset > 0 {
+		prev := page.Offset - page.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if page.Offset+page.Limit < total {
+		links = append(links, link(page.Offset+page.Limit, "next"))
+	}
+	links = append(links, link(last, "last"))
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"odontology-appointments/internal/domain"
+	"odontology-appointments/internal/repository"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPageQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  repository.Page
+		field string
+	}{
+		{"", repository.Page{Limit: repository.DefaultLimit}, ""},
+		{"?limit=10&offset=20&sort=last_name,-id", repository.Page{Limit: 10, Offset: 20, Sort: []repository.SortField{{Field: "last_name"}, {Field: "id", Desc: true}}}, ""},
+		// Los valores fuera de rango se controlan en el servicio con Page.Check
+		{"?limit=0&offset=-1", repository.Page{Limit: 0, Offset: -1}, ""},
+		{"?limit=ten", repository.Page{}, "limit"},
+		{"?offset=1.5", repository.Page{}, "offset"},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			page, err := PageQuery(httptest.NewRequest("GET", "/patients"+test.query, nil))
+			if test.field != "" {
+				var invalid *domain.ValidationError
+				if !errors.As(err, &invalid) || invalid.Fields[0].Field != test.field {
+					t.Fatalf("error = %v, want a validation error on %s", err, test.field)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(page, test.want) {
+				t.Errorf("PageQuery() = %+v, want %+v", page, test.want)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		total int
+		link  string
+	}{
+		{"first page", "?limit=10", 25,
+			`</patients?limit=10&offset=0>; rel="first", </patients?limit=10&offset=10>; rel="next", </patients?limit=10&offset=20>; rel="last"`},
+		{"middle page keeps the filters", "?limit=10&offset=10&status=scheduled", 25,
+			`</patients?limit=10&offset=0&status=scheduled>; rel="first", </patients?limit=10&offset=0&status=scheduled>; rel="prev", </patients?limit=10&offset=20&status=scheduled>; rel="next", </patients?limit=10&offset=20&status=scheduled>; rel="last"`},
+		{"last page", "?limit=10&offset=20", 25,
+			`</patients?limit=10&offset=0>; rel="first", </patients?limit=10&offset=10>; rel="prev", </patients?limit=10&offset=20>; rel="last"`},
+		{"offset not aligned", "?limit=10&offset=5", 25,
+			`</patients?limit=10&offset=0>; rel="first", </patients?limit=10&offset=0>; rel="prev", </patients?limit=10&offset=15>; rel="next", </patients?limit=10&offset=20>; rel="last"`},
+		{"exact pages", "?limit=5&offset=5", 10,
+			`</patients?limit=5&offset=0>; rel="first", </patients?limit=5&offset=0>; rel="prev", </patients?limit=5&offset=5>; rel="last"`},
+		{"empty list", "", 0,
+			`</patients?limit=50&offset=0>; rel="first", </patients?limit=50&offset=0>; rel="last"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/patients"+test.query, nil)
+			page, err := PageQuery(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := httptest.NewRecorder()
+			List(response, request, page, test.total, []int{})
+
+			if response.Code != http.StatusOK || response.Body.String() != "[]\n" {
+				t.Errorf("response = %d %q, want 200 with the list", response.Code, response.Body)
+			}
+			if count := response.Header().Get("X-Total-Count"); count != strconv.Itoa(test.total) {
+				t.Errorf("X-Total-Count = %q, want %d", count, test.total)
+			}
+			if link := response.Header().Get("Link"); link != test.link {
+				t.Errorf("Link = %s\nwant %s", link, test.link)
+			}
+		})
+	}
+}
@@ -4,10 +4,19 @@ import (
 	"log"
 	"net/http"
 	"odontology-appointments/db"
+	"odontology-appointments/internal/apikey"
 	"odontology-appointments/internal/appointment"
+	"odontology-appointments/internal/audit"
+	"odontology-appointments/internal/auth"
+	"odontology-appointments/internal/availability"
 	"odontology-appointments/internal/dentist"
 	"odontology-appointments/internal/patient"
+	"odontology-appointments/internal/repository/sqlstore"
+	"odontology-appointments/internal/schedule"
 	"odontology-appointments/internal/security"
+	"odontology-appointments/internal/user"
+	"os"
+	"strconv"
 
 	_ "odontology-appointments/docs"
 
@@ -15,37 +24,136 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Token de acceso con el formato "Bearer <token>", obtenido en /auth/login. Cada
+// @description ruta exige un permiso según el rol del usuario: admin, receptionist o dentist.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Clave de API de una integración, creada en /api-keys/. Tiene solo los permisos de sus scopes.
 func main() {
 	db := db.InitDB()
 
+	// go run . migrate up | down [steps] | status | dedupe
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(db, os.Args[2:])
+		return
+	}
+	if err := autoMigrate(db); err != nil {
+		log.Print(err)
+		log.Print("answering 503 to every request until the data is fixed and the server restarted")
+		log.Fatal(http.ListenAndServe(":8080", unavailable()))
+	}
+	store := sqlstore.New(db)
+
+	// go run . purge [days]
+	if len(os.Args) > 1 && os.Args[1] == "purge" {
+		runPurge(store, os.Args[2:])
+		return
+	}
+	startPurge(store)
+	// SCHEDULE_REQUIRED=false acepta turnos a cualquier hora con los dentistas sin horarios
+	var options appointment.Options
+	if required, err := strconv.ParseBool(os.Getenv("SCHEDULE_REQUIRED")); err == nil {
+		options.AllowUnscheduled = !required
+	}
+	appointments := appointment.NewService(store, options)
+	dentists := dentist.NewService(store, appointments)
+	patients := patient.NewService(store)
+	schedules := schedule.NewService(store)
+	availabilities := availability.NewService(store)
+	tokens, err := newTokens()
+	if err != nil {
+		log.Fatal(err)
+	}
+	users := user.NewService(store)
+	if err := bootstrapUsers(users); err != nil {
+		log.Fatal(err)
+	}
+	keys := apikey.NewService(store)
+	audits := audit.NewService(store)
+
 	r := mux.NewRouter()
+	r.Use(security.Authenticate(tokens, keys))
+
+	// Auth routes
+	authRouter := r.PathPrefix("/auth").Subrouter()
+	authRouter.HandleFunc("/login", auth.Login(users, tokens)).Methods("POST")
+	authRouter.HandleFunc("/refresh", auth.Refresh(users, tokens)).Methods("POST")
+	authRouter.HandleFunc("/me", security.Middleware(auth.Me())).Methods("GET")
+	authRouter.HandleFunc("/password", security.Middleware(user.ChangePassword(users))).Methods("POST")
+
+	// User routes
+	userRouter := r.PathPrefix("/users").Subrouter()
+	userRouter.HandleFunc("/", security.Require(auth.UsersAdmin, user.GetAllUsers(users))).Methods("GET")
+	userRouter.HandleFunc("/", security.Require(auth.UsersAdmin, user.CreateUser(users))).Methods("POST")
+	userRouter.HandleFunc("/{id}/password", security.Require(auth.UsersAdmin, user.ResetUserPassword(users))).Methods("POST")
+
+	// Audit routes
+	r.HandleFunc("/audit", security.Require(auth.AuditRead, audit.GetAuditLog(audits))).Methods("GET")
+
+	// API key routes
+	keyRouter := r.PathPrefix("/api-keys").Subrouter()
+	keyRouter.HandleFunc("/", security.Require(auth.APIKeysAdmin, apikey.GetAllAPIKeys(keys))).Methods("GET")
+	keyRouter.HandleFunc("/", security.Require(auth.APIKeysAdmin, apikey.CreateAPIKey(keys))).Methods("POST")
+	keyRouter.HandleFunc("/{id}", security.Require(auth.APIKeysAdmin, apikey.RevokeAPIKey(keys))).Methods("DELETE")
 
 	// Dentist routes
 	dentistRouter := r.PathPrefix("/dentists").Subrouter()
-	dentistRouter.HandleFunc("/", dentist.GetAllDentists(db)).Methods("GET")
-	dentistRouter.HandleFunc("/", security.Middleware(dentist.CreateDentist(db))).Methods("POST")
-	dentistRouter.HandleFunc("/{id}", dentist.GetDentistByID(db)).Methods("GET")
-	dentistRouter.HandleFunc("/{id}", security.Middleware(dentist.UpdateDentist(db))).Methods("PUT")
-	dentistRouter.HandleFunc("/{id}", security.Middleware(dentist.PartialUpdateDentist(db))).Methods("PATCH")
-	dentistRouter.HandleFunc("/{id}", security.Middleware(dentist.DeleteDentist(db))).Methods("DELETE")
+	dentistRouter.HandleFunc("/", security.Require(auth.DentistsRead, dentist.GetAllDentists(dentists))).Methods("GET")
+	dentistRouter.HandleFunc("/", security.Require(auth.DentistsWrite, dentist.CreateDentist(dentists))).Methods("POST")
+	dentistRouter.HandleFunc("/{id}", security.Require(auth.DentistsRead, dentist.GetDentistByID(dentists))).Methods("GET")
+	dentistRouter.HandleFunc("/{id}", security.Require(auth.DentistsWrite, dentist.UpdateDentist(dentists))).Methods("PUT")
+	dentistRouter.HandleFunc("/{id}", security.Require(auth.DentistsWrite, dentist.PartialUpdateDentist(dentists))).Methods("PATCH")
+	dentistRouter.HandleFunc("/{id}", security.Require(auth.DentistsAdmin, dentist.DeleteDentist(dentists))).Methods("DELETE")
+	dentistRouter.HandleFunc("/{id}/restore", security.Require(auth.DentistsAdmin, dentist.RestoreDentist(dentists))).Methods("POST")
+	dentistRouter.HandleFunc("/{id}/schedule", security.Require(auth.DentistsRead, schedule.GetSchedule(schedules))).Methods("GET")
+	dentistRouter.HandleFunc("/{id}/schedule", security.Require(auth.DentistsWrite, schedule.UpdateSchedule(schedules))).Methods("PUT")
+	dentistRouter.HandleFunc("/{id}/schedule", security.Require(auth.DentistsWrite, schedule.DeleteSchedule(schedules))).Methods("DELETE")
+	dentistRouter.HandleFunc("/{id}/availability", security.Require(auth.DentistsRead, availability.GetDentistAvailability(availabilities))).Methods("GET")
+	dentistRouter.HandleFunc("/{id}/appointments", security.Require(auth.AppointmentsRead, appointment.GetDentistAppointments(appointments))).Methods("GET")
 
 	// Patient routes
 	patientRouter := r.PathPrefix("/patients").Subrouter()
-	patientRouter.HandleFunc("/", patient.GetAllPatients(db)).Methods("GET")
-	patientRouter.HandleFunc("/", security.Middleware(patient.CreatePatient(db))).Methods("POST")
-	patientRouter.HandleFunc("/{id}", patient.GetPatientByID(db)).Methods("GET")
-	patientRouter.HandleFunc("/{id}", security.Middleware(patient.UpdatePatient(db))).Methods("PUT")
-	patientRouter.HandleFunc("/{id}", security.Middleware(patient.PartialUpdatePatient(db))).Methods("PATCH")
-	patientRouter.HandleFunc("/{id}", security.Middleware(patient.DeletePatient(db))).Methods("DELETE")
+	patientRouter.HandleFunc("/", security.Require(auth.PatientsRead, patient.GetAllPatients(patients))).Methods("GET")
+	patientRouter.HandleFunc("/", security.Require(auth.PatientsWrite, patient.CreatePatient(patients))).Methods("POST")
+	patientRouter.HandleFunc("/search", security.Require(auth.PatientsRead, patient.SearchPatients(patients))).Methods("GET")
+	patientRouter.HandleFunc("/{id}", security.Require(auth.PatientsRead, patient.GetPatientByID(patients))).Methods("GET")
+	patientRouter.HandleFunc("/{id}", security.Require(auth.PatientsWrite, patient.UpdatePatient(patients))).Methods("PUT")
+	patientRouter.HandleFunc("/{id}", security.Require(auth.PatientsWrite, patient.PartialUpdatePatient(patients))).Methods("PATCH")
+	patientRouter.HandleFunc("/{id}", security.Require(auth.PatientsAdmin, patient.DeletePatient(patients))).Methods("DELETE")
+	patientRouter.HandleFunc("/{id}/restore", security.Require(auth.PatientsAdmin, patient.RestorePatient(patients))).Methods("POST")
+	patientRouter.HandleFunc("/{id}/appointments", security.Require(auth.AppointmentsRead, appointment.GetPatientAppointments(appointments))).Methods("GET")
 
 	// Appointment routes
 	appointmentRouter := r.PathPrefix("/appointments").Subrouter()
-	appointmentRouter.HandleFunc("/", appointment.GetAllAppointments(db)).Methods("GET")
-	appointmentRouter.HandleFunc("/", security.Middleware(appointment.CreateAppointment(db))).Methods("POST")
-	appointmentRouter.HandleFunc("/{id}", appointment.GetAppointmentByID(db)).Methods("GET")
-	appointmentRouter.HandleFunc("/{id}", security.Middleware(appointment.UpdateAppointment(db))).Methods("PUT")
-	appointmentRouter.HandleFunc("/{id}", security.Middleware(appointment.PartialUpdateAppointment(db))).Methods("PATCH")
-	appointmentRouter.HandleFunc("/{id}", security.Middleware(appointment.DeleteAppointment(db))).Methods("DELETE")
+	appointmentRouter.HandleFunc("/", security.Require(auth.AppointmentsRead, appointment.GetAllAppointments(appointments))).Methods("GET")
+	appointmentRouter.HandleFunc("/", security.Require(auth.AppointmentsWrite, appointment.CreateAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/by-reference", security.Require(auth.AppointmentsWrite, appointment.CreateAppointmentByReference(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}", security.Require(auth.AppointmentsRead, appointment.GetAppointmentByID(appointments))).Methods("GET")
+	appointmentRouter.HandleFunc("/{id}", security.Require(auth.AppointmentsWrite, appointment.UpdateAppointment(appointments))).Methods("PUT")
+	appointmentRouter.HandleFunc("/{id}", security.Require(auth.AppointmentsWrite, appointment.PartialUpdateAppointment(appointments))).Methods("PATCH")
+	appointmentRouter.HandleFunc("/{id}", security.Require(auth.AppointmentsAdmin, appointment.DeleteAppointment(appointments))).Methods("DELETE")
+	appointmentRouter.HandleFunc("/{id}/restore", security.Require(auth.AppointmentsAdmin, appointment.RestoreAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}/confirm", security.Require(auth.AppointmentsWrite, appointment.ConfirmAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}/cancel", security.Require(auth.AppointmentsWrite, appointment.CancelAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}/complete", security.Require(auth.AppointmentsWrite, appointment.CompleteAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}/no-show", security.Require(auth.AppointmentsWrite, appointment.NoShowAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}/status-history", security.Require(auth.AppointmentsRead, appointment.GetStatusHistory(appointments))).Methods("GET")
+	appointmentRouter.HandleFunc("/{id}/reschedule", security.Require(auth.AppointmentsWrite, appointment.RescheduleAppointment(appointments))).Methods("POST")
+	appointmentRouter.HandleFunc("/{id}/reschedules", security.Require(auth.AppointmentsRead, appointment.GetRescheduleHistory(appointments))).Methods("GET")
+
+	// Appointment series routes
+	seriesRouter := r.PathPrefix("/appointment-series").Subrouter()
+	seriesRouter.HandleFunc("/", security.Require(auth.AppointmentsWrite, appointment.CreateSeries(appointments))).Methods("POST")
+	seriesRouter.HandleFunc("/{id}", security.Require(auth.AppointmentsRead, appointment.GetSeriesByID(appointments))).Methods("GET")
+	seriesRouter.HandleFunc("/{id}/appointments/{appointmentId}", security.Require(auth.AppointmentsWrite, appointment.UpdateSeriesAppointments(appointments))).Methods("PATCH")
+
+	// Availability routes
+	r.HandleFunc("/availability", security.Require(auth.AppointmentsRead, availability.GetAvailability(availabilities))).Methods("GET")
+
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
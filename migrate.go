@@ -0,0 +1,121 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"odontology-appointments/db"
+	"odontology-appointments/internal/web"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = "usage: odontology-appointments migrate up | down [steps] | status | dedupe"
+
+// runMigrate ejecuta el comando migrate: up aplica las migraciones pendientes, down revierte
+// las últimas (una por defecto), status lista cada migración con su estado y dedupe marca los
+// DNI y matrículas repetidos que impiden migrar (ver db.DedupeKeys).
+func runMigrate(database *sql.DB, args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := db.MigrateUp(database)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatal(migrateUsage)
+			}
+			steps = n
+		}
+		reverted, err := db.MigrateDown(database, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+	case "status":
+		status, err := db.Status(database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, migration := range status {
+			state := "pending"
+			if migration.AppliedAt != "" {
+				state = "applied " + migration.AppliedAt
+			}
+			fmt.Printf("%04d_%-30s %s\n", migration.Version, migration.Name, state)
+		}
+	case "dedupe":
+		changes, err := db.DedupeKeys(database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+		if len(changes) == 0 {
+			fmt.Println("no duplicated values")
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
+
+// autoMigrate aplica las migraciones pendientes al iniciar el servidor salvo que
+// DB_AUTO_MIGRATE sea false. Si está desactivado solo advierte si hay pendientes. Si los datos
+// impiden aplicar una migración (db.ErrPrecheck) devuelve el error en lugar de terminar, para
+// que el servidor informe el problema; cualquier otro error lo termina.
+func autoMigrate(database *sql.DB) error {
+	if enabled, err := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE")); err == nil && !enabled {
+		status, err := db.Status(database)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, migration := range status {
+			if migration.AppliedAt == "" {
+				log.Printf("migration %04d_%s is pending, run: migrate up", migration.Version, migration.Name)
+			}
+		}
+		return nil
+	}
+
+	applied, err := db.MigrateUp(database)
+	for _, migration := range applied {
+		log.Printf("applied migration %04d_%s", migration.Version, migration.Name)
+	}
+	if errors.Is(err, db.ErrPrecheck) {
+		return err
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return nil
+}
+
+// unavailable responde 503 a todos los pedidos. Se usa cuando la base no pudo migrarse por sus
+// datos: el detalle va al log, no al cliente, porque incluye DNI de pacientes.
+func unavailable() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.Error(w, http.StatusServiceUnavailable, "The database must be fixed before it can be migrated, see the server log")
+	})
+}
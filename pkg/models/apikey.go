@@ -0,0 +1,26 @@
+package models
+
+// APIKey es una clave de API de una integración. La clave en sí no se guarda: solo se
+// muestra una vez, al crearla (ver NewAPIKey).
+type APIKey struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`                   // Integración que usa la clave
+	Prefix     string   `json:"prefix"`                 // Primeros caracteres de la clave, para reconocerla
+	Scopes     []string `json:"scopes"`                 // Permisos, por ejemplo appointments:read
+	CreatedAt  string   `json:"created_at"`             // RFC 3339
+	LastUsedAt string   `json:"last_used_at,omitempty"` // RFC 3339, vacío si nunca se usó
+	RevokedAt  string   `json:"revoked_at,omitempty"`   // RFC 3339, solo en las claves revocadas
+	Hash       string   `json:"-"`                      // SHA-256 de la clave
+}
+
+// APIKeyRequest es el cuerpo para crear una clave de API.
+type APIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"` // Integración que usa la clave
+	Scopes []string `json:"scopes"`                           // Permisos, por ejemplo appointments:read
+}
+
+// NewAPIKey es la respuesta al crear una clave de API. Key no se puede volver a consultar.
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"` // Clave a enviar en el encabezado X-API-Key
+}
@@ -1,10 +1,97 @@
 package models
 
+// MaxDuration es la duración máxima en minutos de un turno o serie. La controla la regla
+// duration de las etiquetas validate.
+const MaxDuration = 8 * 60
+
 type Appointment struct {
 	ID          int    `json:"id"`
-	Date        string `json:"date"`
-	Time        string `json:"time"`
-	Description string `json:"description"`
-	PatientID   int    `json:"patient_id"`
-	DentistID   int    `json:"dentist_id"`
+	Date        string `json:"date" validate:"required,date,notpast=time"`
+	Time        string `json:"time" validate:"required,time"`
+	Duration    int    `json:"duration" validate:"min=1,duration"` // Duración en minutos
+	EndTime     string `json:"end_time"`                           // Calculado a partir de time y duration
+	Description string `json:"description" validate:"max=500"`
+	Status      string `json:"status"` // scheduled, confirmed, completed, cancelled o no_show
+	PatientID   int    `json:"patient_id" validate:"required,min=1"`
+	DentistID   int    `json:"dentist_id" validate:"required,min=1"`
+	SeriesID    int    `json:"series_id,omitempty"`  // Serie recurrente a la que pertenece, si corresponde
+	DeletedAt   string `json:"deleted_at,omitempty"` // RFC 3339, solo en los registros eliminados
+}
+
+// ExpandedAppointment es un turno con los datos del paciente y del dentista incluidos, para
+// las consultas con ?expand=patient,dentist. Solo se completan los pedidos.
+type ExpandedAppointment struct {
+	Appointment
+	Patient *PatientSummary `json:"patient,omitempty"`
+	Dentist *DentistSummary `json:"dentist,omitempty"`
+}
+
+// PatientSummary son los datos de un paciente que se incluyen en un turno.
+type PatientSummary struct {
+	ID        int    `json:"id"`
+	LastName  string `json:"last_name"`
+	FirstName string `json:"first_name"`
+	DNI       string `json:"dni"`
+}
+
+// DentistSummary son los datos de un dentista que se incluyen en un turno.
+type DentistSummary struct {
+	ID        int    `json:"id"`
+	LastName  string `json:"last_name"`
+	FirstName string `json:"first_name"`
+	License   string `json:"license"`
+}
+
+// AppointmentByReference permite reservar un turno identificando al paciente por su DNI
+// y al dentista por su matrícula en lugar de sus IDs internos.
+type AppointmentByReference struct {
+	Date           string `json:"date" validate:"required,date,notpast=time"`
+	Time           string `json:"time" validate:"required,time"`
+	Duration       int    `json:"duration" validate:"duration"` // Duración en minutos
+	Description    string `json:"description" validate:"max=500"`
+	PatientDNI     string `json:"patient_dni" validate:"required,dni"`
+	DentistLicense string `json:"dentist_license" validate:"required,license"`
+}
+
+// StatusChangeRequest es el cuerpo opcional de los cambios de estado de un turno.
+type StatusChangeRequest struct {
+	Reason string `json:"reason"` // Obligatorio al cancelar
+}
+
+// StatusChange registra un cambio de estado de un turno.
+type StatusChange struct {
+	ID            int    `json:"id"`
+	AppointmentID int    `json:"appointment_id"`
+	FromStatus    string `json:"from_status"`
+	ToStatus      string `json:"to_status"`
+	Reason        string `json:"reason"`
+	ChangedBy     string `json:"changed_by"`
+	ChangedAt     string `json:"changed_at"` // RFC 3339
+}
+
+// RescheduleRequest es el cuerpo para mover un turno a otro horario. Si duration o
+// dentist_id no se indican se mantienen los del turno.
+type RescheduleRequest struct {
+	Date      string `json:"date" validate:"required,date,notpast=time"`
+	Time      string `json:"time" validate:"required,time"`
+	Duration  int    `json:"duration" validate:"duration"`
+	DentistID int    `json:"dentist_id" validate:"min=0"`
+	Reason    string `json:"reason" validate:"max=500"`
+}
+
+// Reschedule registra un cambio de horario de un turno.
+type Reschedule struct {
+	ID                int    `json:"id"`
+	AppointmentID     int    `json:"appointment_id"`
+	PreviousDate      string `json:"previous_date"`
+	PreviousTime      string `json:"previous_time"`
+	PreviousDuration  int    `json:"previous_duration"`
+	PreviousDentistID int    `json:"previous_dentist_id"`
+	NewDate           string `json:"new_date"`
+	NewTime           string `json:"new_time"`
+	NewDuration       int    `json:"new_duration"`
+	NewDentistID      int    `json:"new_dentist_id"`
+	Reason            string `json:"reason"`
+	ChangedBy         string `json:"changed_by"`
+	ChangedAt         string `json:"changed_at"` // RFC 3339
 }
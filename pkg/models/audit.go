@@ -0,0 +1,19 @@
+package models
+
+// AuditEntry registra un alta, modificación o baja de un dentista, paciente, turno o serie.
+type AuditEntry struct {
+	ID         int                    `json:"id"`
+	Actor      string                 `json:"actor"`     // Usuario o clave de API que hizo el cambio
+	Timestamp  string                 `json:"timestamp"` // RFC 3339
+	Resource   string                 `json:"resource" example:"appointment"`
+	ResourceID int                    `json:"resource_id"`
+	Action     string                 `json:"action" example:"patch"` // create, update, patch, delete, restore, status o reschedule
+	Changes    map[string]AuditChange `json:"changes"`                // Campos que cambiaron, por nombre JSON
+}
+
+// AuditChange es el valor anterior y el posterior de un campo. En un alta el anterior es
+// null.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// LoginRequest son las credenciales para iniciar sesión.
+type LoginRequest struct {
+	Username string `json:"username"` // Nombre de usuario
+	Password string `json:"password"` // Contraseña
+}
+
+// RefreshRequest pide un nuevo par de tokens a partir de un token de renovación.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"` // Token de renovación recibido al iniciar sesión
+}
+
+// TokenPair es la respuesta de un inicio de sesión o una renovación.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`  // JWT a enviar como "Authorization: Bearer <token>"
+	RefreshToken string `json:"refresh_token"` // JWT para pedir un nuevo par cuando vence el de acceso
+	TokenType    string `json:"token_type"`    // Siempre "Bearer"
+	ExpiresIn    int    `json:"expires_in"`    // Segundos hasta que vence el token de acceso
+}
+
+// User es el usuario autenticado.
+type User struct {
+	ID        int      `json:"id"`                   // ID del usuario
+	Username  string   `json:"username"`             // Nombre de usuario
+	Role      string   `json:"role,omitempty"`       // admin, receptionist o dentist
+	DentistID int      `json:"dentist_id,omitempty"` // Dentista del usuario, solo con el rol dentist
+	Scopes    []string `json:"scopes,omitempty"`     // Permisos de una clave de API, que no tiene rol
+}
+
+// UserAccount es una cuenta de usuario. La contraseña solo se guarda como hash.
+type UserAccount struct {
+	ID                int    `json:"id"`
+	Username          string `json:"username"`
+	Role              string `json:"role"`                   // admin, receptionist o dentist
+	DentistID         int    `json:"dentist_id,omitempty"`   // Dentista vinculado, solo con el rol dentist
+	FailedLogins      int    `json:"failed_logins"`          // Intentos fallidos seguidos
+	LockedUntil       string `json:"locked_until,omitempty"` // RFC 3339, mientras la cuenta está bloqueada
+	PasswordChangedAt string `json:"password_changed_at"`    // RFC 3339
+	CreatedAt         string `json:"created_at"`             // RFC 3339
+	PasswordHash      string `json:"-"`
+}
+
+// UserRequest es el cuerpo para crear una cuenta de usuario.
+type UserRequest struct {
+	Username  string `json:"username" validate:"required,max=50"`
+	Password  string `json:"password" validate:"required"`
+	Role      string `json:"role" validate:"required,oneof=admin|receptionist|dentist"`
+	DentistID int    `json:"dentist_id" validate:"min=0"` // Obligatorio con el rol dentist
+}
+
+// PasswordChange es el cuerpo para que un usuario cambie su propia contraseña.
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+// PasswordReset es el cuerpo para que un administrador asigne una nueva contraseña.
+type PasswordReset struct {
+	NewPassword string `json:"new_password" validate:"required"`
+}
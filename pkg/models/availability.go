@@ -0,0 +1,9 @@
+package models
+
+// AvailableSlot es un horario libre en el que se puede reservar un turno.
+type AvailableSlot struct {
+	DentistID int    `json:"dentist_id"`
+	Date      string `json:"date"`       // YYYY-MM-DD
+	StartTime string `json:"start_time"` // HH:MM
+	EndTime   string `json:"end_time"`   // HH:MM
+}
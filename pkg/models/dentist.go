@@ -2,7 +2,8 @@ package models
 
 type Dentist struct {
 	ID        int    `json:"id"`
-	LastName  string `json:"last_name"`
-	FirstName string `json:"first_name"`
-	License   string `json:"license"`
+	LastName  string `json:"last_name" validate:"required,max=100"`
+	FirstName string `json:"first_name" validate:"required,max=100"`
+	License   string `json:"license" validate:"required,license"`
+	DeletedAt string `json:"deleted_at,omitempty"` // RFC 3339, solo en los registros eliminados
 }
@@ -2,6 +2,27 @@ package models
 
 // Error representa una estructura de error genérica para la API.
 type Error struct {
-	Code    int    `json:"code"`    // Código del error (por ejemplo, 400, 404)
-	Message string `json:"message"` // Mensaje descriptivo del error
+	Code    int          `json:"code"`              // Código del error (por ejemplo, 400, 404)
+	Type    string       `json:"error"`             // Tipo de error legible por máquina (por ejemplo, not_found)
+	Message string       `json:"message"`           // Mensaje descriptivo del error
+	Details []FieldError `json:"details,omitempty"` // Errores por campo, si corresponde
+}
+
+// FieldError describe un problema con un campo puntual del pedido.
+type FieldError struct {
+	Field   string `json:"field"`   // Nombre del campo en el JSON
+	Message string `json:"message"` // Qué está mal en el campo
+}
+
+// ConflictError se devuelve cuando un turno se superpone con otro ya existente.
+type ConflictError struct {
+	Error
+	ConflictingAppointmentID int `json:"conflicting_appointment_id"` // ID del turno con el que choca
+}
+
+// DuplicateError es la respuesta 409 cuando otro registro ya usa un valor que debe ser único.
+type DuplicateError struct {
+	Error
+	Field      string `json:"field"`       // Campo con el valor repetido
+	ExistingID int    `json:"existing_id"` // ID del registro que ya usa el valor
 }
@@ -2,9 +2,10 @@ package models
 
 type Patient struct {
 	ID               int    `json:"id"`
-	LastName         string `json:"last_name"`
-	FirstName        string `json:"first_name"`
-	Address          string `json:"address"`
-	DNI              string `json:"dni"`
-	RegistrationDate string `json:"registration_date"`
+	LastName         string `json:"last_name" validate:"required,max=100"`
+	FirstName        string `json:"first_name" validate:"required,max=100"`
+	Address          string `json:"address" validate:"max=200"`
+	DNI              string `json:"dni" validate:"required,dni"`
+	RegistrationDate string `json:"registration_date" validate:"date"` // YYYY-MM-DD, por defecto la fecha del alta
+	DeletedAt        string `json:"deleted_at,omitempty"`              // RFC 3339, solo en los registros eliminados
 }
@@ -0,0 +1,17 @@
+package models
+
+// ScheduleBlock es una franja semanal de atención de un dentista.
+type ScheduleBlock struct {
+	ID        int             `json:"id"`
+	DentistID int             `json:"dentist_id"`
+	DayOfWeek int             `json:"day_of_week"` // 0 = domingo ... 6 = sábado
+	StartTime string          `json:"start_time"`  // HH:MM
+	EndTime   string          `json:"end_time"`    // HH:MM
+	Breaks    []ScheduleBreak `json:"breaks"`
+}
+
+// ScheduleBreak es una pausa dentro de una franja de atención.
+type ScheduleBreak struct {
+	StartTime string `json:"start_time"` // HH:MM
+	EndTime   string `json:"end_time"`   // HH:MM
+}
@@ -0,0 +1,43 @@
+package models
+
+// AppointmentSeries es una regla de repetición que genera turnos periódicos para un
+// paciente y un dentista. Se indica count o until, no ambos.
+type AppointmentSeries struct {
+	ID           int           `json:"id"`
+	PatientID    int           `json:"patient_id" validate:"required,min=1"`
+	DentistID    int           `json:"dentist_id" validate:"required,min=1"`
+	StartDate    string        `json:"start_date" validate:"required,date,notpast=time"` // YYYY-MM-DD, primera ocurrencia
+	Time         string        `json:"time" validate:"required,time"`                    // HH:MM
+	Duration     int           `json:"duration" validate:"duration"`                     // Duración en minutos
+	Description  string        `json:"description" validate:"max=500"`
+	Frequency    string        `json:"frequency" validate:"required,oneof=weekly|monthly"` // weekly o monthly
+	Interval     int           `json:"interval" validate:"min=0"`                          // Cada cuántas semanas o meses, por defecto 1
+	Count        int           `json:"count,omitempty" validate:"min=0,max=104"`
+	Until        string        `json:"until,omitempty" validate:"date"` // YYYY-MM-DD, inclusive
+	Appointments []Appointment `json:"appointments,omitempty"`
+}
+
+// SeriesUpdate son los cambios a aplicar sobre ocurrencias de una serie. Los campos vacíos
+// no se modifican.
+type SeriesUpdate struct {
+	Time        string  `json:"time" validate:"time"`
+	Duration    int     `json:"duration" validate:"duration"`
+	DentistID   int     `json:"dentist_id" validate:"min=0"`
+	Description *string `json:"description" validate:"max=500"`
+	Reason      string  `json:"reason" validate:"max=500"` // Motivo que queda en el historial de reprogramaciones
+}
+
+// SkippedOccurrence es una ocurrencia de una serie que no pudo crearse o modificarse.
+type SkippedOccurrence struct {
+	Date                     string `json:"date"`
+	AppointmentID            int    `json:"appointment_id,omitempty"`
+	Reason                   string `json:"reason"`
+	ConflictingAppointmentID int    `json:"conflicting_appointment_id,omitempty"`
+}
+
+// SeriesResult informa los turnos creados o modificados de una serie y los que se omitieron.
+type SeriesResult struct {
+	Series       *AppointmentSeries  `json:"series,omitempty"`
+	Appointments []Appointment       `json:"appointments"`
+	Skipped      []SkippedOccurrence `json:"skipped"`
+}
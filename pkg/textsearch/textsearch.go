@@ -0,0 +1,53 @@
+// Package textsearch normaliza texto para búsquedas sin distinguir mayúsculas ni acentos.
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Accented y Plain son las letras acentuadas que se reemplazan y su versión sin acento, en
+// el mismo orden. La migración de búsqueda de PostgreSQL usa los mismos valores con
+// translate(), así que deben cambiarse juntos.
+const (
+	Accented = "àáâãäåçèéêëìíîïñòóôõöùúûüýÿ"
+	Plain    = "aaaaaaceeeeiiiinooooouuuuyy"
+)
+
+var folding = func() map[rune]rune {
+	plain := []rune(Plain)
+	m := make(map[rune]rune, len(plain))
+	for i, r := range []rune(Accented) {
+		m[r] = plain[i]
+	}
+	return m
+}()
+
+// Fold pasa el texto a minúsculas y le quita los acentos, por ejemplo "Pérez" a "perez".
+func Fold(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if plain, ok := folding[r]; ok {
+			return plain
+		}
+		return r
+	}, s)
+}
+
+// Terms divide el texto normalizado con Fold en palabras formadas solo por letras y dígitos,
+// igual que los índices de texto de la base.
+func Terms(s string) []string {
+	return strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// HasPrefix indica si alguna palabra de text empieza con term. term debe estar normalizado.
+func HasPrefix(text, term string) bool {
+	for _, word := range Terms(text) {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package timeslot
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DateLayout es el formato de fecha que usa la API (ISO 8601).
+	DateLayout = "2006-01-02"
+	// TimeLayout es el formato de hora que usa la API (HH:MM).
+	TimeLayout = "15:04"
+)
+
+// ErrInvalid se devuelve cuando la fecha o la hora no respetan los formatos de la API.
+var ErrInvalid = errors.New("invalid date or time format")
+
+// Slot representa un intervalo de tiempo semiabierto [Start, End).
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// New arma un Slot a partir de la fecha y hora de un turno y su duración.
+func New(date, clock string, duration time.Duration) (Slot, error) {
+	start, err := time.ParseInLocation(DateLayout+" "+TimeLayout, date+" "+clock, time.Local)
+	if err != nil {
+		return Slot{}, ErrInvalid
+	}
+	return Slot{Start: start, End: start.Add(duration)}, nil
+}
+
+// Overlaps indica si dos intervalos comparten al menos un instante.
+func (s Slot) Overlaps(other Slot) bool {
+	return s.Start.Before(other.End) && other.Start.Before(s.End)
+}
+
+// ClockMinutes convierte una hora HH:MM en minutos desde la medianoche.
+func ClockMinutes(clock string) (int, error) {
+	t, err := time.Parse(TimeLayout, clock)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
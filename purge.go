@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"odontology-appointments/internal/repository"
+	"odontology-appointments/internal/retention"
+	"os"
+	"strconv"
+	"time"
+)
+
+const purgeUsage = "usage: odontology-appointments purge [days], or set RETENTION_DAYS"
+
+// purgeInterval es cada cuánto se purgan los registros eliminados mientras corre el servidor.
+const purgeInterval = 24 * time.Hour
+
+// retentionDays devuelve los días que se conservan los registros eliminados según
+// RETENTION_DAYS, o 0 si no está definida.
+func retentionDays() (int, error) {
+	value := os.Getenv("RETENTION_DAYS")
+	if value == "" {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 {
+		return 0, fmt.Errorf("RETENTION_DAYS must be a positive number of days, got %q", value)
+	}
+	return days, nil
+}
+
+// runPurge ejecuta el comando purge: borra definitivamente los registros eliminados hace más
+// de los días indicados, o de RETENTION_DAYS si no se indican.
+func runPurge(store repository.Store, args []string) {
+	days, err := retentionDays()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(args) > 0 {
+		days, err = strconv.Atoi(args[0])
+		if err != nil || days < 1 {
+			log.Fatal(purgeUsage)
+		}
+	}
+	if days == 0 {
+		log.Fatal(purgeUsage)
+	}
+
+	result, err := retention.Purge(store, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("purged %d appointments, %d series, %d patients and %d dentists deleted more than %d days ago\n",
+		result.Appointments, result.Series, result.Patients, result.Dentists, days)
+}
+
+// startPurge inicia la purga diaria de registros eliminados si RETENTION_DAYS está definida.
+// Sin ella los registros eliminados se conservan indefinidamente.
+func startPurge(store repository.Store) {
+	days, err := retentionDays()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if days == 0 {
+		return
+	}
+	go retention.Run(context.Background(), store, time.Duration(days)*24*time.Hour, purgeInterval)
+}